@@ -0,0 +1,565 @@
+package jsonFilter
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// Context shared by all the value comparisons of a filter application.
+// A comparator isn't thread safe, create a new one for each filter application
+type comparator struct {
+	// Collator for the string comparison, nil if no collation is defined in the options
+	collator *collate.Collator
+	// Reference time for the relative time values, like now-7d
+	now time.Time
+	// Separator of the interval bounds, like .. in [18..65)
+	rangeSeparator string
+	// Prefix of the references to other keys, doubled in the escaped literal values, like $$promo
+	referencePrefix string
+	// Compare the string values case insensitively
+	fold bool
+	// Compare the string values after Unicode NFC normalization
+	normalize bool
+	// Case folder of the string values
+	caser cases.Caser
+}
+
+// Create the comparator according with the options
+func newComparator(o *Options) *comparator {
+	c := &comparator{
+		now:             time.Now(),
+		rangeSeparator:  o.RangeValueSeparator,
+		referencePrefix: o.FieldReferencePrefix,
+		fold:            o.CaseInsensitive,
+		normalize:       o.UnicodeNormalization,
+		caser:           cases.Fold(),
+	}
+	if o.Collation != "" {
+		c.collator = collate.New(language.Make(o.Collation))
+	}
+	return c
+}
+
+// Get a copy of the comparator which compares the string values case insensitively and Unicode normalized
+func (c *comparator) insensitive() *comparator {
+	ic := *c
+	ic.fold = true
+	ic.normalize = true
+	return &ic
+}
+
+// Transform the string according with the case folding and the normalization of the comparator
+func (c *comparator) normalizeString(s string) string {
+	if c.fold {
+		s = c.caser.String(s)
+	}
+	if c.normalize {
+		s = norm.NFC.String(s)
+	}
+	return s
+}
+
+// Check if the entry value matches the filter value: included in the interval if the filter value is an interval,
+// else equal to the filter value
+func (c *comparator) match(ev reflect.Value, v string) bool {
+	if iv, ok := parseInterval(v, c.rangeSeparator); ok {
+		return c.inInterval(ev, iv)
+	}
+	return c.equal(ev, v)
+}
+
+// Check if the entry value is included in the interval. The bounds are compared like the greater than and lower than
+// operators, according with the entry value type
+func (c *comparator) inInterval(ev reflect.Value, iv interval) bool {
+	if iv.lower != "" {
+		r, ok := c.compare(ev, iv.lower)
+		if !ok || r < 0 || (r == 0 && !iv.lowerInclusive) {
+			return false
+		}
+	}
+	if iv.upper != "" {
+		r, ok := c.compare(ev, iv.upper)
+		if !ok || r > 0 || (r == 0 && !iv.upperInclusive) {
+			return false
+		}
+	}
+	return true
+}
+
+// Check if the entry value is equal to the filter value. The comparison is directed by the type of the entry value:
+//   - time values are compared on the instant, whatever the location
+//   - duration values are compared on their length, like 90s and 1m30s
+//   - numeric values are compared numerically, like 1e+06 and 1000000, and the numeric enums by their name
+//   - string values are compared after case folding and normalization, if enabled
+//   - other values are compared on their string representation
+func (c *comparator) equal(ev reflect.Value, v string) bool {
+	ev = concreteValue(ev)
+	if !ev.IsValid() {
+		return false
+	}
+
+	if ev.Type() == timeType {
+		vt, err := parseTime(v, c.now)
+		evt, ok := timeValue(ev)
+		return err == nil && ok && evt.Equal(vt)
+	}
+	if ev.Type() == durationType {
+		vd, err := parseDuration(v)
+		return err == nil && time.Duration(ev.Int()) == vd
+	}
+	if isNumericKind(ev.Kind()) {
+		r, ok := compareNumber(ev, v)
+		if !ok {
+			// The numeric enums are equal to their name, like level=high
+			if _, err := parseNumber(v); err != nil {
+				s, ok := stringValue(ev)
+				return ok && c.normalizeString(s) == c.normalizeString(v)
+			}
+		}
+		return ok && r == 0
+	}
+	if ev.Kind() == reflect.String {
+		return c.normalizeString(ev.String()) == c.normalizeString(v)
+	}
+	return fmt.Sprint(ev) == v
+}
+
+// Check if the entry value, a string, matches at least one filter value with the string function, like
+// strings.HasPrefix. The strings are compared after case folding and normalization, if enabled.
+// Return false if the entry value isn't a string
+func (c *comparator) matchString(ev reflect.Value, vs []string, fn func(s string, v string) bool) bool {
+	ev = concreteValue(ev)
+	if !ev.IsValid() || ev.Kind() != reflect.String {
+		return false
+	}
+	es := c.normalizeString(ev.String())
+	for _, v := range vs {
+		if fn(es, c.normalizeString(v)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Compare the entry value with the filter value. The comparison is directed by the type of the entry value:
+//   - numeric values are compared numerically
+//   - string values are compared lexicographically, with the collation rules if a collator is defined, after case
+//     folding and normalization, if enabled
+//   - time values are compared chronologically
+//   - duration values are compared on their length
+//
+// Return -1, 0 or 1 if the entry value is respectively lower, equal or greater than the filter value.
+// Return false if the values can't be compared (not supported type or filter value not convertible)
+func (c *comparator) compare(ev reflect.Value, v string) (int, bool) {
+	ev = concreteValue(ev)
+	if !ev.IsValid() {
+		return 0, false
+	}
+
+	if ev.Type() == timeType {
+		vt, err := parseTime(v, c.now)
+		evt, ok := timeValue(ev)
+		if err != nil || !ok {
+			return 0, false
+		}
+		switch {
+		case evt.Before(vt):
+			return -1, true
+		case evt.After(vt):
+			return 1, true
+		}
+		return 0, true
+	}
+	if ev.Type() == durationType {
+		vd, err := parseDuration(v)
+		if err != nil {
+			return 0, false
+		}
+		return compareInt(ev.Int(), int64(vd)), true
+	}
+
+	if isNumericKind(ev.Kind()) {
+		return compareNumber(ev, v)
+	}
+	switch ev.Kind() {
+	case reflect.String:
+		es, vs := c.normalizeString(ev.String()), c.normalizeString(v)
+		if c.collator != nil {
+			return c.collator.CompareString(es, vs), true
+		}
+		return strings.Compare(es, vs), true
+	}
+	return 0, false
+}
+
+// Check if the entry value is equal to the value of another key of the entry. The comparison is directed by the types
+// of the values, like with the filter values. The values of different types are never equal
+func (c *comparator) equalValues(ev reflect.Value, rv reflect.Value) bool {
+	if r, ok := c.compareValues(ev, rv); ok {
+		return r == 0
+	}
+	// The NaN values aren't comparable, and never equal
+	ev, rv = concreteValue(ev), concreteValue(rv)
+	return ev.IsValid() && rv.IsValid() && ev.Type() == rv.Type() && !isNumericKind(ev.Kind()) && fmt.Sprint(ev) == fmt.Sprint(rv)
+}
+
+// Compare the entry value with the value of another key of the entry. The comparison is directed by the types of the
+// values, like with the filter values: numeric values, whatever their types, string values, time values and duration
+// values can be compared together.
+// Return -1, 0 or 1 if the entry value is respectively lower, equal or greater than the other value.
+// Return false if the values can't be compared (not supported or different types)
+func (c *comparator) compareValues(ev reflect.Value, rv reflect.Value) (int, bool) {
+	ev, rv = concreteValue(ev), concreteValue(rv)
+	if !ev.IsValid() || !rv.IsValid() {
+		return 0, false
+	}
+
+	switch {
+	case ev.Type() == timeType || rv.Type() == timeType:
+		if ev.Type() != rv.Type() {
+			return 0, false
+		}
+		evt, eok := timeValue(ev)
+		rvt, rok := timeValue(rv)
+		if !eok || !rok {
+			return 0, false
+		}
+		switch {
+		case evt.Before(rvt):
+			return -1, true
+		case evt.After(rvt):
+			return 1, true
+		}
+		return 0, true
+	case ev.Type() == durationType || rv.Type() == durationType:
+		if ev.Type() != rv.Type() {
+			return 0, false
+		}
+		return compareInt(ev.Int(), rv.Int()), true
+	case isNumericKind(ev.Kind()) && isNumericKind(rv.Kind()):
+		en, eok := bigNumber(ev)
+		rn, rok := bigNumber(rv)
+		if !eok || !rok {
+			return 0, false
+		}
+		return en.Cmp(rn), true
+	case ev.Kind() == reflect.String && rv.Kind() == reflect.String:
+		return c.compare(ev, rv.String())
+	}
+	return 0, false
+}
+
+// Convert exactly a numeric value in big float, whatever its type.
+// Return false if the value is NaN
+func bigNumber(v reflect.Value) (*big.Float, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return new(big.Float).SetInt64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Float).SetUint64(v.Uint()), true
+	}
+	if math.IsNaN(v.Float()) {
+		return nil, false
+	}
+	return new(big.Float).SetFloat64(v.Float()), true
+}
+
+// Compare numerically the entry value with the filter value.
+// The integer entry values are compared exactly, even beyond the float precision (2^53). The float entry values are
+// compared with the filter value parsed with the same precision (float32 or float64).
+// Return false if the filter value isn't numeric, or if one of the values is NaN
+func compareNumber(ev reflect.Value, v string) (int, bool) {
+	switch ev.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return compareInt(ev.Int(), n), true
+		}
+		// Not an integer value, like 10.5 or 1e+06
+		n, err := parseNumber(v)
+		if err != nil {
+			return 0, false
+		}
+		return new(big.Float).SetInt64(ev.Int()).Cmp(n), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if n, err := strconv.ParseUint(v, 10, 64); err == nil {
+			return compareUint(ev.Uint(), n), true
+		}
+		// Not an unsigned integer value, like -1, 10.5 or 1e+06
+		n, err := parseNumber(v)
+		if err != nil {
+			return 0, false
+		}
+		return new(big.Float).SetUint64(ev.Uint()).Cmp(n), true
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(v, ev.Type().Bits())
+		if err != nil {
+			return 0, false
+		}
+		return compareFloat(ev.Float(), n)
+	}
+	return 0, false
+}
+
+// Precision, in bits, of the parsed numeric filter values compared to integer values. Large enough to keep exact the
+// integer values and the usual decimal values
+const numberPrecision = 256
+
+// Parse a numeric filter value, in any decimal format, like 10, -10.5 or 1e+06
+func parseNumber(v string) (*big.Float, error) {
+	n, _, err := big.ParseFloat(v, 10, numberPrecision, big.ToNearestEven)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("the value %s isn't numeric", v))
+	}
+	return n, nil
+}
+
+// Get the time of the time value.
+// Return false if the time can't be read, like a time in an unexported struct field
+func timeValue(v reflect.Value) (time.Time, bool) {
+	if !v.CanInterface() {
+		return time.Time{}, false
+	}
+	return v.Interface().(time.Time), true
+}
+
+func isNumericKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// In case of interface (map of interface for example), get the concrete value. In case of type with a canonical value
+// (FilterValuer), get the canonical value.
+// Return an invalid value if the interface or the canonical value is nil
+func concreteValue(ev reflect.Value) reflect.Value {
+	if ev.Kind() == reflect.Interface {
+		if ev.IsNil() {
+			return reflect.Value{}
+		}
+		ev = ev.Elem()
+	}
+	if !ev.IsValid() {
+		return ev
+	}
+	return canonicalValue(ev)
+}
+
+func compareInt(a, b int64) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
+
+func compareUint(a, b uint64) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
+
+// Compare the floats. Return false if one of them is NaN, which isn't comparable
+func compareFloat(a, b float64) (int, bool) {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return 0, false
+	}
+	if a < b {
+		return -1, true
+	}
+	if a > b {
+		return 1, true
+	}
+	return 0, true
+}
+
+// Check if the filter value can be matched with the values of the leaf type: the bounds in case of interval value,
+// else the value for equality.
+// Return an error if the value isn't compliant with the type
+func checkMatchValue(v string, sep string, t reflect.Type) error {
+	iv, ok := parseInterval(v, sep)
+	if !ok {
+		return checkEqualValue(v, t)
+	}
+	for _, b := range []string{iv.lower, iv.upper} {
+		if b == "" {
+			continue
+		}
+		if err := checkRangeValue(b, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Check if the filter value can be compared for equality with the values of the leaf type.
+// Return an error if the value isn't compliant with the type
+func checkEqualValue(v string, t reflect.Type) error {
+	switch t {
+	case timeType:
+		_, err := parseTime(v, time.Now())
+		return err
+	case durationType:
+		_, err := parseDuration(v)
+		return err
+	}
+	// The numeric enums can be compared with their name, like level=high
+	if isNumericKind(t.Kind()) && !hasStringValue(t) {
+		if _, err := parseNumber(v); err != nil {
+			return errors.New(fmt.Sprintf("the value %s isn't numeric and can't be compared to the numeric type %s", v, t))
+		}
+	}
+	return nil
+}
+
+// Check if the filter value can be compared (greater than, lower than) with the values of the leaf type.
+// Return an error if the type doesn't support the range comparison or if the value isn't compliant with the type
+func checkRangeValue(v string, t reflect.Type) error {
+	switch t {
+	case timeType:
+		_, err := parseTime(v, time.Now())
+		return err
+	case durationType:
+		_, err := parseDuration(v)
+		return err
+	}
+
+	if isNumericKind(t.Kind()) {
+		if _, err := parseNumber(v); err != nil {
+			return errors.New(fmt.Sprintf("the value %s isn't numeric and can't be compared to the numeric type %s", v, t))
+		}
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Interface:
+		// Always comparable. Interface values are checked only when the filter is applied
+	default:
+		return errors.New(fmt.Sprintf("the type %s doesn't support 'greater than' and 'lower than' comparison", t))
+	}
+	return nil
+}
+
+// Layouts accepted for the time values, in the order of test
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02",
+}
+
+// Parse a time value of the filter. These formats are accepted
+//   - RFC 3339, like 2020-01-31T10:00:00Z or 2020-01-31T10:00:00.123+01:00
+//   - Date only, like 2020-01-31. The time is midnight UTC
+//   - Relative to now, like now, now-7d, now+1h30m. The units are these of Go duration, plus d (day) and w (week)
+func parseTime(v string, now time.Time) (time.Time, error) {
+	if strings.HasPrefix(v, "now") {
+		d, err := parseRelativeDuration(strings.TrimPrefix(v, "now"))
+		if err != nil {
+			return time.Time{}, errors.New(fmt.Sprintf("the value %s isn't a valid relative time: %s", v, err))
+		}
+		return now.Add(d), nil
+	}
+
+	for _, l := range timeLayouts {
+		if t, err := time.Parse(l, v); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New(fmt.Sprintf("the value %s isn't a valid time. Use RFC 3339, date only or relative to now format", v))
+}
+
+// Parse the signed offset of a relative time, like -7d or +1h30m. An empty offset means no offset
+func parseRelativeDuration(o string) (time.Duration, error) {
+	if o == "" {
+		return 0, nil
+	}
+	if o[0] != '+' && o[0] != '-' {
+		return 0, errors.New("the offset must start with + or -")
+	}
+	return parseDuration(o)
+}
+
+// Parse a duration value of the filter. These formats are accepted
+//   - Go duration, like 30s, 1h30m or -1.5h
+//   - Number of days or weeks, like 7d or 2w
+//   - Integer number of nanoseconds, like 30000000000
+func parseDuration(v string) (time.Duration, error) {
+	if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.Duration(n), nil
+	}
+
+	// Day and week units aren't supported by Go duration
+	for u, ud := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if strings.HasSuffix(v, u) {
+			n, err := strconv.Atoi(strings.TrimSuffix(v, u))
+			if err != nil {
+				return 0, errors.New(fmt.Sprintf("the value %s isn't a valid duration", v))
+			}
+			return time.Duration(n) * ud, nil
+		}
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, errors.New(fmt.Sprintf("the value %s isn't a valid duration, like 30s or 1h30m", v))
+	}
+	return d, nil
+}
+
+// Interval of values, like [18..65) or (..10]
+type interval struct {
+	// Lower bound. Empty means unbounded
+	lower string
+	// Upper bound. Empty means unbounded
+	upper string
+	// Inclusive bounds ([ or ]) or exclusive bounds (( or ))
+	lowerInclusive bool
+	upperInclusive bool
+}
+
+// Parse an interval value of the filter, like [18..65) with .. as separator. The lower bound starts with [ if it's
+// inclusive or ( if it's exclusive. The upper bound ends with ] if it's inclusive or ) if it's exclusive. An empty
+// bound means unbounded, like [18..).
+// Return false if the value isn't an interval
+func parseInterval(v string, sep string) (iv interval, ok bool) {
+	if sep == "" || len(v) < len(sep)+2 {
+		return
+	}
+	first, last := v[0], v[len(v)-1]
+	if (first != '[' && first != '(') || (last != ']' && last != ')') {
+		return
+	}
+	b := strings.Split(v[1:len(v)-1], sep)
+	if len(b) != 2 {
+		return
+	}
+	return interval{
+		lower:          b[0],
+		upper:          b[1],
+		lowerInclusive: first == '[',
+		upperInclusive: last == ']',
+	}, true
+}
@@ -0,0 +1,803 @@
+package jsonFilter
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// Reference time for the relative time tests
+var testNow = time.Date(2020, 1, 31, 10, 0, 0, 0, time.UTC)
+
+// Day after the reference time, addressable for the time pointer tests
+var testTomorrow = testNow.AddDate(0, 0, 1)
+
+func Test_comparator_equal(t *testing.T) {
+	type args struct {
+		ev reflect.Value
+		v  string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "string",
+			args: args{ev: reflect.ValueOf("value1"), v: "value1"},
+			want: true,
+		},
+		{
+			name: "time RFC 3339 in other location",
+			args: args{ev: reflect.ValueOf(testNow), v: "2020-01-31T11:00:00+01:00"},
+			want: true,
+		},
+		{
+			name: "time date only",
+			args: args{ev: reflect.ValueOf(time.Date(2020, 1, 31, 0, 0, 0, 0, time.UTC)), v: "2020-01-31"},
+			want: true,
+		},
+		{
+			name: "time relative",
+			args: args{ev: reflect.ValueOf(testNow.AddDate(0, 0, -7)), v: "now-7d"},
+			want: true,
+		},
+		{
+			name: "time not matching",
+			args: args{ev: reflect.ValueOf(testNow), v: "2020-01-31"},
+			want: false,
+		},
+		{
+			name: "time with invalid value",
+			args: args{ev: reflect.ValueOf(testNow), v: "yesterday"},
+			want: false,
+		},
+		{
+			name: "duration go format",
+			args: args{ev: reflect.ValueOf(90 * time.Second), v: "1m30s"},
+			want: true,
+		},
+		{
+			name: "duration nanoseconds",
+			args: args{ev: reflect.ValueOf(30 * time.Second), v: "30000000000"},
+			want: true,
+		},
+		{
+			name: "int with exponent format",
+			args: args{ev: reflect.ValueOf(1000000), v: "1e+06"},
+			want: true,
+		},
+		{
+			name: "float with integer format",
+			args: args{ev: reflect.ValueOf(1e+06), v: "1000000"},
+			want: true,
+		},
+		{
+			name: "float32 with float64 value",
+			args: args{ev: reflect.ValueOf(float32(0.1)), v: "0.1"},
+			want: true,
+		},
+		{
+			name: "int64 beyond float precision",
+			args: args{ev: reflect.ValueOf(int64(9007199254740993)), v: "9007199254740992"},
+			want: false,
+		},
+		{
+			name: "int with no numeric value",
+			args: args{ev: reflect.ValueOf(1), v: "one"},
+			want: false,
+		},
+		{
+			name: "nil interface",
+			args: args{ev: reflect.ValueOf(map[string]interface{}{"k": nil}).MapIndex(reflect.ValueOf("k")), v: "<nil>"},
+			want: false,
+		},
+		{
+			name: "NaN not equal to a number",
+			args: args{ev: reflect.ValueOf(math.NaN()), v: "5"},
+			want: false,
+		},
+		{
+			name: "NaN not equal to NaN",
+			args: args{ev: reflect.ValueOf(math.NaN()), v: "NaN"},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &comparator{now: testNow}
+			if got := c.equal(tt.args.ev, tt.args.v); got != tt.want {
+				t.Errorf("equal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_comparator_match(t *testing.T) {
+	type args struct {
+		ev reflect.Value
+		v  string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "not an interval",
+			args: args{ev: reflect.ValueOf("[a..b]"), v: "[a..b]"},
+			want: false,
+		},
+		{
+			name: "equal value",
+			args: args{ev: reflect.ValueOf(18), v: "18"},
+			want: true,
+		},
+		{
+			name: "inclusive lower bound",
+			args: args{ev: reflect.ValueOf(18), v: "[18..65)"},
+			want: true,
+		},
+		{
+			name: "exclusive upper bound",
+			args: args{ev: reflect.ValueOf(65), v: "[18..65)"},
+			want: false,
+		},
+		{
+			name: "exclusive lower bound",
+			args: args{ev: reflect.ValueOf(10.0), v: "(10..20]"},
+			want: false,
+		},
+		{
+			name: "unbounded upper",
+			args: args{ev: reflect.ValueOf(int64(9007199254740993)), v: "[9007199254740993..)"},
+			want: true,
+		},
+		{
+			name: "string",
+			args: args{ev: reflect.ValueOf("m"), v: "[a..m]"},
+			want: true,
+		},
+		{
+			name: "time",
+			args: args{ev: reflect.ValueOf(testNow.AddDate(0, 0, -1)), v: "[now-7d..now)"},
+			want: true,
+		},
+		{
+			name: "not comparable",
+			args: args{ev: reflect.ValueOf(true), v: "[false..true]"},
+			want: false,
+		},
+		{
+			name: "NaN not in interval",
+			args: args{ev: reflect.ValueOf(math.NaN()), v: "[1..2]"},
+			want: false,
+		},
+		{
+			name: "float32 NaN not in interval",
+			args: args{ev: reflect.ValueOf(float32(math.NaN())), v: "(0..)"},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &comparator{now: testNow, rangeSeparator: ".."}
+			if got := c.match(tt.args.ev, tt.args.v); got != tt.want {
+				t.Errorf("match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_comparator_compare(t *testing.T) {
+	type args struct {
+		ev       reflect.Value
+		v        string
+		collator *collate.Collator
+	}
+	tests := []struct {
+		name   string
+		args   args
+		want   int
+		wantOk bool
+	}{
+		{
+			name:   "int greater",
+			args:   args{ev: reflect.ValueOf(11), v: "10"},
+			want:   1,
+			wantOk: true,
+		},
+		{
+			name:   "float lower",
+			args:   args{ev: reflect.ValueOf(-1.5), v: "-1.2"},
+			want:   -1,
+			wantOk: true,
+		},
+		{
+			name:   "int64 beyond float precision",
+			args:   args{ev: reflect.ValueOf(int64(9007199254740993)), v: "9007199254740992"},
+			want:   1,
+			wantOk: true,
+		},
+		{
+			name:   "uint64 max",
+			args:   args{ev: reflect.ValueOf(uint64(18446744073709551615)), v: "18446744073709551614"},
+			want:   1,
+			wantOk: true,
+		},
+		{
+			name:   "uint with negative value",
+			args:   args{ev: reflect.ValueOf(uint(0)), v: "-1"},
+			want:   1,
+			wantOk: true,
+		},
+		{
+			name:   "int with decimal value",
+			args:   args{ev: reflect.ValueOf(10), v: "10.5"},
+			want:   -1,
+			wantOk: true,
+		},
+		{
+			name:   "int with no numeric value",
+			args:   args{ev: reflect.ValueOf(11), v: "abc"},
+			want:   0,
+			wantOk: false,
+		},
+		{
+			name:   "string lexicographic",
+			args:   args{ev: reflect.ValueOf("Zoe"), v: "alice"},
+			want:   -1,
+			wantOk: true,
+		},
+		{
+			name:   "string with collation",
+			args:   args{ev: reflect.ValueOf("Zoe"), v: "alice", collator: collate.New(language.English)},
+			want:   1,
+			wantOk: true,
+		},
+		{
+			name:   "interface of string",
+			args:   args{ev: reflect.ValueOf(map[string]interface{}{"k": "b"}).MapIndex(reflect.ValueOf("k")), v: "a"},
+			want:   1,
+			wantOk: true,
+		},
+		{
+			name:   "time before",
+			args:   args{ev: reflect.ValueOf(testNow.AddDate(0, 0, -8)), v: "now-1w"},
+			want:   -1,
+			wantOk: true,
+		},
+		{
+			name:   "time after date only",
+			args:   args{ev: reflect.ValueOf(testNow), v: "2020-01-31"},
+			want:   1,
+			wantOk: true,
+		},
+		{
+			name:   "duration greater",
+			args:   args{ev: reflect.ValueOf(time.Minute), v: "30s"},
+			want:   1,
+			wantOk: true,
+		},
+		{
+			name:   "duration lower in days",
+			args:   args{ev: reflect.ValueOf(time.Hour), v: "1d"},
+			want:   -1,
+			wantOk: true,
+		},
+		{
+			name:   "duration with invalid value",
+			args:   args{ev: reflect.ValueOf(time.Hour), v: "1 hour"},
+			want:   0,
+			wantOk: false,
+		},
+		{
+			name:   "bool not comparable",
+			args:   args{ev: reflect.ValueOf(true), v: "false"},
+			want:   0,
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &comparator{collator: tt.args.collator, now: testNow}
+			got, gotOk := c.compare(tt.args.ev, tt.args.v)
+			if got != tt.want || gotOk != tt.wantOk {
+				t.Errorf("compare() = %v, %v, want %v, %v", got, gotOk, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_comparator_insensitive(t *testing.T) {
+	type args struct {
+		ev reflect.Value
+		v  string
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantEqual   bool
+		wantCompare int
+	}{
+		{
+			name:      "case folding",
+			args:      args{ev: reflect.ValueOf("OPEN"), v: "open"},
+			wantEqual: true,
+		},
+		{
+			name:      "unicode case folding",
+			args:      args{ev: reflect.ValueOf("STRASSE"), v: "straße"},
+			wantEqual: true,
+		},
+		{
+			name:      "NFC normalization",
+			args:      args{ev: reflect.ValueOf("caf\u00e9"), v: "cafe\u0301"},
+			wantEqual: true,
+		},
+		{
+			name:        "range comparison",
+			args:        args{ev: reflect.ValueOf("Bob"), v: "alice"},
+			wantEqual:   false,
+			wantCompare: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newComparator(defaultOption)
+			if c.equal(tt.args.ev, tt.args.v) && tt.args.ev.String() != tt.args.v {
+				t.Errorf("equal() = true, want false without insensitive")
+			}
+			ic := c.insensitive()
+			if got := ic.equal(tt.args.ev, tt.args.v); got != tt.wantEqual {
+				t.Errorf("equal() = %v, want %v", got, tt.wantEqual)
+			}
+			if got, _ := ic.compare(tt.args.ev, tt.args.v); got != tt.wantCompare {
+				t.Errorf("compare() = %v, want %v", got, tt.wantCompare)
+			}
+		})
+	}
+}
+
+func Test_comparator_normalizeString(t *testing.T) {
+	tests := []struct {
+		name      string
+		fold      bool
+		normalize bool
+		s         string
+		want      string
+	}{
+		{name: "nothing", s: "Cafe\u0301", want: "Cafe\u0301"},
+		{name: "fold", fold: true, s: "Cafe\u0301", want: "cafe\u0301"},
+		{name: "normalize", normalize: true, s: "Cafe\u0301", want: "Caf\u00e9"},
+		{name: "fold and normalize", fold: true, normalize: true, s: "Cafe\u0301", want: "caf\u00e9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newComparator(&Options{CaseInsensitive: tt.fold, UnicodeNormalization: tt.normalize})
+			if got := c.normalizeString(tt.s); got != tt.want {
+				t.Errorf("normalizeString() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_comparator_matchString(t *testing.T) {
+	tests := []struct {
+		name        string
+		ev          reflect.Value
+		vs          []string
+		fn          func(s string, v string) bool
+		insensitive bool
+		want        bool
+	}{
+		{name: "contains", ev: reflect.ValueOf("value1"), vs: []string{"lu"}, fn: strings.Contains, want: true},
+		{name: "contains one of values", ev: reflect.ValueOf("value1"), vs: []string{"x", "e1"}, fn: strings.Contains, want: true},
+		{name: "not contains", ev: reflect.ValueOf("value1"), vs: []string{"x", "y"}, fn: strings.Contains, want: false},
+		{name: "prefix", ev: reflect.ValueOf("eu-west"), vs: []string{"eu-"}, fn: strings.HasPrefix, want: true},
+		{name: "case sensitive prefix", ev: reflect.ValueOf("EU-west"), vs: []string{"eu-"}, fn: strings.HasPrefix, want: false},
+		{name: "insensitive prefix", ev: reflect.ValueOf("EU-west"), vs: []string{"eu-"}, fn: strings.HasPrefix, insensitive: true, want: true},
+		{name: "suffix", ev: reflect.ValueOf("file.json"), vs: []string{".json"}, fn: strings.HasSuffix, want: true},
+		{name: "string in interface", ev: reflect.ValueOf([]interface{}{"value1"}).Index(0), vs: []string{"val"}, fn: strings.HasPrefix, want: true},
+		{name: "not a string", ev: reflect.ValueOf(12), vs: []string{"1"}, fn: strings.HasPrefix, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newComparator(defaultOption)
+			if tt.insensitive {
+				c = c.insensitive()
+			}
+			if got := c.matchString(tt.ev, tt.vs, tt.fn); got != tt.want {
+				t.Errorf("matchString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_comparator_compareValues(t *testing.T) {
+	tests := []struct {
+		name      string
+		ev        interface{}
+		rv        interface{}
+		want      int
+		wantOk    bool
+		wantEqual bool
+	}{
+		{name: "int and float", ev: 2, rv: 1.5, want: 1, wantOk: true},
+		{name: "large int and uint", ev: int64(1<<62 + 1), rv: uint64(1<<62 + 1), want: 0, wantOk: true, wantEqual: true},
+		{name: "float32 and float64", ev: float32(0.5), rv: 0.75, want: -1, wantOk: true},
+		{name: "string", ev: "a", rv: "b", want: -1, wantOk: true},
+		{name: "time", ev: testNow, rv: testNow.Add(-time.Hour), want: 1, wantOk: true},
+		{name: "duration", ev: time.Second, rv: time.Minute, want: -1, wantOk: true},
+		{name: "duration and int", ev: time.Second, rv: int64(time.Second)},
+		{name: "int and string", ev: 1, rv: "1"},
+		{name: "bool", ev: true, rv: true, wantEqual: true},
+		{name: "NaN", ev: math.NaN(), rv: 1.0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newComparator(defaultOption)
+			got, gotOk := c.compareValues(reflect.ValueOf(tt.ev), reflect.ValueOf(tt.rv))
+			if got != tt.want || gotOk != tt.wantOk {
+				t.Errorf("compareValues() = %v, %v, want %v, %v", got, gotOk, tt.want, tt.wantOk)
+			}
+			if gotEqual := c.equalValues(reflect.ValueOf(tt.ev), reflect.ValueOf(tt.rv)); gotEqual != tt.wantEqual {
+				t.Errorf("equalValues() = %v, want %v", gotEqual, tt.wantEqual)
+			}
+		})
+	}
+}
+
+func Test_parseTime(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       string
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name: "RFC 3339",
+			v:    "2020-01-31T10:00:00Z",
+			want: testNow,
+		},
+		{
+			name: "RFC 3339 nano",
+			v:    "2020-01-31T10:00:00.5Z",
+			want: testNow.Add(500 * time.Millisecond),
+		},
+		{
+			name: "date only",
+			v:    "2020-01-31",
+			want: time.Date(2020, 1, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "now",
+			v:    "now",
+			want: testNow,
+		},
+		{
+			name: "now plus go duration",
+			v:    "now+1h30m",
+			want: testNow.Add(90 * time.Minute),
+		},
+		{
+			name: "now minus days",
+			v:    "now-2d",
+			want: testNow.AddDate(0, 0, -2),
+		},
+		{
+			name:    "now without sign",
+			v:       "now7d",
+			wantErr: true,
+		},
+		{
+			name:    "not a time",
+			v:       "31/01/2020",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTime(tt.v, testNow)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseTime() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       string
+		want    time.Duration
+		wantErr bool
+	}{
+		{
+			name: "go duration",
+			v:    "1h30m",
+			want: 90 * time.Minute,
+		},
+		{
+			name: "negative go duration",
+			v:    "-1.5h",
+			want: -90 * time.Minute,
+		},
+		{
+			name: "days",
+			v:    "7d",
+			want: 7 * 24 * time.Hour,
+		},
+		{
+			name: "weeks",
+			v:    "2w",
+			want: 14 * 24 * time.Hour,
+		},
+		{
+			name: "nanoseconds",
+			v:    "30000000000",
+			want: 30 * time.Second,
+		},
+		{
+			name:    "invalid days",
+			v:       "1.5d",
+			wantErr: true,
+		},
+		{
+			name:    "not a duration",
+			v:       "30 seconds",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDuration(tt.v)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseDuration() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("parseDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseInterval(t *testing.T) {
+	tests := []struct {
+		name   string
+		v      string
+		want   interval
+		wantOk bool
+	}{
+		{
+			name:   "inclusive and exclusive",
+			v:      "[18..65)",
+			want:   interval{lower: "18", upper: "65", lowerInclusive: true},
+			wantOk: true,
+		},
+		{
+			name:   "exclusive and inclusive",
+			v:      "(1.5..2.5]",
+			want:   interval{lower: "1.5", upper: "2.5", upperInclusive: true},
+			wantOk: true,
+		},
+		{
+			name:   "unbounded",
+			v:      "[..]",
+			want:   interval{lowerInclusive: true, upperInclusive: true},
+			wantOk: true,
+		},
+		{
+			name:   "no brackets",
+			v:      "18..65",
+			wantOk: false,
+		},
+		{
+			name:   "no separator",
+			v:      "[18,65]",
+			wantOk: false,
+		},
+		{
+			name:   "several separators",
+			v:      "[1..2..3]",
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotOk := parseInterval(tt.v, "..")
+			if gotOk != tt.wantOk || got != tt.want {
+				t.Errorf("parseInterval() = %v, %v, want %v, %v", got, gotOk, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestFilter_timeValuesInFilter(t *testing.T) {
+	entries := []testStruct{
+		{RootString: "value1", RootTime: testNow},
+		{RootString: "value2", RootTime: testTomorrow},
+	}
+	tests := []struct {
+		name   string
+		filter string
+		want   []testStruct
+	}{
+		{
+			name:   "RFC 3339 value",
+			filter: "timeRoot>2020-01-31T10:00:00Z",
+			want:   entries[1:],
+		},
+		{
+			name:   "RFC 3339 value with offset and other filter",
+			filter: "timeRoot=2020-01-31T11:00:00+01:00:stringRoot=value1",
+			want:   entries[:1],
+		},
+		{
+			name:   "RFC 3339 values in interval",
+			filter: "timeRoot=[2020-01-31T00:00:00Z..2020-01-31T12:00:00.5Z)",
+			want:   entries[:1],
+		},
+		{
+			name:   "date only value",
+			filter: "timeRoot<2020-02-01",
+			want:   entries[:1],
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{}
+			if err := f.Init(tt.filter, testStruct{}); err != nil {
+				t.Fatalf("Init() error = %v", err)
+			}
+			got, err := f.ApplyFilter(entries)
+			if err != nil {
+				t.Fatalf("ApplyFilter() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ApplyFilter() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter_unexportedTimeValues(t *testing.T) {
+	type meta struct {
+		Updated time.Time
+	}
+	type entry struct {
+		Name    string
+		created time.Time
+		meta    meta
+		extra   interface{}
+	}
+	entries := []entry{
+		{Name: "value1", created: testNow, meta: meta{Updated: testNow}, extra: testNow},
+		{Name: "value2", extra: 2},
+	}
+	tests := []struct {
+		name    string
+		filter  string
+		want    []entry
+		wantErr bool
+	}{
+		{
+			name:    "unexported time field",
+			filter:  "created>2020-01-01",
+			wantErr: true,
+		},
+		{
+			name:    "time field in unexported struct field",
+			filter:  "meta.Updated=2020-01-31T10:00:00Z",
+			wantErr: true,
+		},
+		{
+			name:    "unexported time field in function",
+			filter:  "year(created)=2020",
+			wantErr: true,
+		},
+		{
+			name:   "time in unexported interface field not comparable",
+			filter: "extra>2020-01-01",
+			want:   []entry{},
+		},
+		{
+			name:   "time in unexported interface field not equal",
+			filter: "extra!=2020-01-31T10:00:00Z",
+			want:   entries,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{}
+			err := f.Init(tt.filter, entry{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Init() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got, err := f.ApplyFilter(entries)
+			if err != nil {
+				t.Fatalf("ApplyFilter() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ApplyFilter() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter_NaNValues(t *testing.T) {
+	entries := []testStruct{
+		{RootString: "value1", RootFloat: float32(math.NaN()), RootInt: 1},
+		{RootString: "value2", RootFloat: 0, RootInt: 0},
+		{RootString: "value3", RootFloat: 5, RootInt: 1},
+	}
+	tests := []struct {
+		name   string
+		filter string
+		want   []string
+	}{
+		{
+			name:   "NaN not equal",
+			filter: "floatRoot=5",
+			want:   []string{"value3"},
+		},
+		{
+			name:   "NaN not in interval",
+			filter: "floatRoot=[0..10]",
+			want:   []string{"value2", "value3"},
+		},
+		{
+			name:   "NaN not greater",
+			filter: "floatRoot>-1",
+			want:   []string{"value2", "value3"},
+		},
+		{
+			name:   "NaN different",
+			filter: "floatRoot!=0",
+			want:   []string{"value1", "value3"},
+		},
+		{
+			name:   "NaN not equal to the referenced key",
+			filter: "floatRoot=$floatRoot",
+			want:   []string{"value2", "value3"},
+		},
+		{
+			name:   "NaN expression result not comparable",
+			filter: "floatRoot/intRoot<10",
+			want:   []string{"value3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{}
+			if err := f.Init(tt.filter, testStruct{}); err != nil {
+				t.Fatalf("Init() error = %v", err)
+			}
+			got, err := f.ApplyFilter(entries)
+			if err != nil {
+				t.Fatalf("ApplyFilter() error = %v", err)
+			}
+			// The NaN values aren't equal, the entries are compared by their string
+			var names []string
+			for _, e := range got.([]testStruct) {
+				names = append(names, e.RootString)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("ApplyFilter() got = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
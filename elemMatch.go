@@ -0,0 +1,127 @@
+package jsonFilter
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Operator of the element match filters, like array{a=1:b=2}. The filter has sub filters instead of values
+const elemMatchOperator = "{}"
+
+// Get the key and the sub filters of an element match filter, like array{a=1:b=2}.
+// Return false if the filter isn't an element match, for example if the braces are in the filter value, like k={v}
+func (f *Filter) getElemMatchAndKey(filter string) (k string, sub string, ok bool) {
+	i := strings.Index(filter, "{")
+	if i < 0 || !strings.HasSuffix(filter, "}") {
+		return "", "", false
+	}
+	k, sub = filter[:i], filter[i+1:len(filter)-1]
+
+	// The key must not contain an operator, else the braces are in the filter values
+	if kv, _ := f.getFilterAndValue(k); isKeyValuesValidPair(kv) {
+		return "", "", false
+	}
+
+	// The opening brace must be closed by the last one
+	depth := 0
+	for _, c := range sub {
+		switch c {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		}
+		if depth < 0 {
+			return "", "", false
+		}
+	}
+	return k, sub, depth == 0
+}
+
+// Find the struct field names in relation with the key of an element match filter, and compile the sub filters against
+// the type of the elements. The key must lead to an array or a map of struct (or of map).
+// Return the key with the struct field names, the type of the key and the compiled sub filters
+func (f *Filter) compileElemMatch(k string, sub []kov, t reflect.Type) (ck string, ct reflect.Type, csub []kov, err error) {
+	ck, ct, err = f.compileComposedKey(k, t)
+	if err != nil {
+		return
+	}
+
+	et := elemType(ct)
+	if et == nil {
+		return "", nil, nil, errors.New(fmt.Sprintf("The Filter key %s isn't an array or a map of structs and can't be matched per element", ck))
+	}
+	// The keys of the sub filters are allowed or denied with the key of the element match as prefix
+	prefix := f.keyPrefix
+	f.keyPrefix += ck + f.options.ComposedKeySeparator
+	csub, err = f.compileFilters(sub, et)
+	f.keyPrefix = prefix
+	return
+}
+
+// Get the type of the elements of an array or a map, pointers and sub arrays being invisible.
+// Return nil if the type isn't an array or a map, or if the elements are neither struct nor map
+func elemType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+		t = leafType(t)
+	case reflect.Map:
+		t = leafType(t.Elem())
+	default:
+		return nil
+	}
+
+	if isLeafType(t) || (t.Kind() != reflect.Struct && t.Kind() != reflect.Map) {
+		return nil
+	}
+	return t
+}
+
+// Check if at least one element of the key matches all the sub filters of the element match filter
+func (f *Filter) matchElemMatch(kov kov, evs reflect.Value, c *comparator) bool {
+	for _, e := range f.findElementInKey(kov.Key, evs) {
+		m := true
+		for _, sub := range kov.Sub {
+			if !f.matchFilter(sub, e, c) {
+				m = false
+				break
+			}
+		}
+		if m {
+			return true
+		}
+	}
+	return false
+}
+
+// Find all the elements of the arrays and the maps found at the end of the composed key.
+// The nil pointers are ignored
+func (f *Filter) findElementInKey(k string, evs reflect.Value) []reflect.Value {
+	r := make([]reflect.Value, 0) //result
+	for _, cv := range f.findContainerInComposedKey(k, evs) {
+		v := cv.value
+		//In case of pointer
+		if v.Kind() == reflect.Ptr {
+			//If the pointer lead to nil value
+			if v.IsNil() {
+				continue
+			}
+			v = v.Elem()
+		}
+
+		// The elements of the map are its values, else the elements of the array are extracted
+		if v.Kind() == reflect.Map {
+			for _, mk := range v.MapKeys() {
+				r = appendLeafValue(r, v.MapIndex(mk))
+			}
+		} else {
+			r = appendLeafValue(r, v)
+		}
+	}
+	return r
+}
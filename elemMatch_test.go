@@ -0,0 +1,131 @@
+package jsonFilter
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestFilter_getElemMatchAndKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  string
+		wantK   string
+		wantSub string
+		wantOk  bool
+	}{
+		{
+			name:    "element match",
+			filter:  "k1.k2{k3=v1:k4>v2}",
+			wantK:   "k1.k2",
+			wantSub: "k3=v1:k4>v2",
+			wantOk:  true,
+		},
+		{
+			name:    "nested element match",
+			filter:  "k1{k2{k3=v1}:k4=v2}",
+			wantK:   "k1",
+			wantSub: "k2{k3=v1}:k4=v2",
+			wantOk:  true,
+		},
+		{
+			name:   "braces in value",
+			filter: "k1={v1}",
+		},
+		{
+			name:   "not closed at the end",
+			filter: "k1{k2=v1}k3",
+		},
+		{
+			name:   "closed before the end",
+			filter: "k1{k2=v1}{k3=v2}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{
+				options: defaultOption,
+			}
+			gotK, gotSub, gotOk := f.getElemMatchAndKey(tt.filter)
+			if gotK != tt.wantK || gotSub != tt.wantSub || gotOk != tt.wantOk {
+				t.Errorf("getElemMatchAndKey() = %v, %v, %v, want %v, %v, %v", gotK, gotSub, gotOk, tt.wantK, tt.wantSub, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_elemType(t *testing.T) {
+	tests := []struct {
+		name string
+		t    reflect.Type
+		want reflect.Type
+	}{
+		{name: "array of struct", t: reflect.TypeOf([]SubStruct{}), want: reflect.TypeOf(SubStruct{})},
+		{name: "array of struct pointers", t: reflect.TypeOf([]*testStruct{}), want: reflect.TypeOf(testStruct{})},
+		{name: "array of map", t: reflect.TypeOf([]map[string]string{}), want: reflect.TypeOf(map[string]string{})},
+		{name: "map of array of struct", t: reflect.TypeOf(map[string][]SubStruct{}), want: reflect.TypeOf(SubStruct{})},
+		{name: "array of string", t: reflect.TypeOf([]string{})},
+		{name: "map of string", t: reflect.TypeOf(map[string]string{})},
+		{name: "struct", t: reflect.TypeOf(SubStruct{})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := elemType(tt.t); got != tt.want {
+				t.Errorf("elemType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter_findElementInKey(t *testing.T) {
+	entry := reflect.ValueOf(testStruct{
+		RootArray: []SubStruct{{SubString: "val1"}, {SubString: "val2"}},
+		RootMap: map[string]SubStruct{
+			"entry1": {SubString: "val3"},
+		},
+		RootMapArrayOfStruct: map[string][]SubStruct{
+			"entry1": {{SubString: "val4"}, {SubString: "val5"}},
+		},
+		RootPtrStruct: &testStruct{},
+	})
+	tests := []struct {
+		name string
+		k    string
+		want []string
+	}{
+		{
+			name: "array of struct",
+			k:    "RootArray",
+			want: []string{"{val1}", "{val2}"},
+		},
+		{
+			name: "map of struct",
+			k:    "RootMap",
+			want: []string{"{val3}"},
+		},
+		{
+			name: "map of array of struct",
+			k:    "RootMapArrayOfStruct",
+			want: []string{"{val4}", "{val5}"},
+		},
+		{
+			name: "nil array",
+			k:    "RootPtrStruct.RootArray",
+			want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{
+				options: defaultOption,
+			}
+			got := make([]string, 0)
+			for _, e := range f.findElementInKey(tt.k, entry) {
+				got = append(got, fmt.Sprint(e))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findElementInKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
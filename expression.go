@@ -0,0 +1,308 @@
+package jsonFilter
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Arithmetic operators of the expressions on the key side, like price*quantity>100
+const arithmeticOperators = "+-*/"
+
+var (
+	int64Type   = reflect.TypeOf(int64(0))
+	float64Type = reflect.TypeOf(float64(0))
+)
+
+// Arithmetic expression on the key side, like price*quantity. The expression is a tree of binary operations, the leaves
+// being keys or numeric literals
+type expression struct {
+	// Arithmetic operator of the operation, empty for a leaf
+	op          string
+	left, right *expression
+	// Key of the leaf, empty for a literal
+	key string
+	// Numeric literal of the leaf
+	literal string
+}
+
+// Get the expression with the operations in parenthesis, like (price*(quantity+1))
+func (e *expression) String() string {
+	switch {
+	case e.op != "":
+		return "(" + e.left.String() + e.op + e.right.String() + ")"
+	case e.key != "":
+		return e.key
+	}
+	return e.literal
+}
+
+// Get the keys of the leaves of the expression, from left to right
+func (e *expression) keys() []string {
+	switch {
+	case e.op != "":
+		return append(e.left.keys(), e.right.keys()...)
+	case e.key != "":
+		return []string{e.key}
+	}
+	return nil
+}
+
+// Check if the key is a compiled expression. The compiled expressions are in parenthesis, like (Price*Quantity)
+func isExpression(k string) bool {
+	return strings.HasPrefix(k, "(")
+}
+
+// Parser of the arithmetic expressions. The multiplications and the divisions are evaluated before the additions and
+// the subtractions, the parenthesis change the evaluation order
+type expressionParser struct {
+	s string
+	i int
+}
+
+// Parse the arithmetic expression, like price*quantity or (end-start)/60
+func parseExpression(s string) (*expression, error) {
+	p := &expressionParser{s: s}
+	e, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if p.i != len(s) {
+		return nil, errors.New(fmt.Sprintf("unexpected %s in the expression %s", s[p.i:], s))
+	}
+	return e, nil
+}
+
+// Parse the additions and the subtractions of products
+func (p *expressionParser) parseSum() (*expression, error) {
+	return p.parseOperation("+-", p.parseProduct)
+}
+
+// Parse the multiplications and the divisions of operands
+func (p *expressionParser) parseProduct() (*expression, error) {
+	return p.parseOperation("*/", p.parseOperand)
+}
+
+// Parse the operations, evaluated from left to right, with the operators on the sub expressions
+func (p *expressionParser) parseOperation(ops string, parseSub func() (*expression, error)) (*expression, error) {
+	l, err := parseSub()
+	for err == nil && p.i < len(p.s) && strings.IndexByte(ops, p.s[p.i]) >= 0 {
+		op := p.s[p.i : p.i+1]
+		p.i++
+		var r *expression
+		if r, err = parseSub(); err == nil {
+			l = &expression{op: op, left: l, right: r}
+		}
+	}
+	return l, err
+}
+
+// Parse an expression in parenthesis, a key or a numeric literal
+func (p *expressionParser) parseOperand() (*expression, error) {
+	if p.i < len(p.s) && p.s[p.i] == '(' {
+		p.i++
+		e, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if p.i >= len(p.s) || p.s[p.i] != ')' {
+			return nil, errors.New(fmt.Sprintf("missing closing parenthesis in the expression %s", p.s))
+		}
+		p.i++
+		return e, nil
+	}
+
+	// The operand ends at the next operator or closing parenthesis, outside the function parenthesis and the array
+	// selectors. A numeric literal can be negative
+	start, depth := p.i, 0
+	if p.i < len(p.s) && p.s[p.i] == '-' {
+		p.i++
+	}
+	for ; p.i < len(p.s); p.i++ {
+		c := p.s[p.i]
+		if depth == 0 && (strings.IndexByte(arithmeticOperators, c) >= 0 || c == ')') {
+			break
+		}
+		switch c {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		}
+	}
+
+	o := p.s[start:p.i]
+	if _, err := strconv.ParseFloat(o, 64); err == nil {
+		return &expression{literal: o}, nil
+	}
+	if o == "" || strings.HasPrefix(o, "-") {
+		return nil, errors.New(fmt.Sprintf("missing operand at position %d in the expression %s", start, p.s))
+	}
+	return &expression{key: o}, nil
+}
+
+// Find the struct field names in relation with the keys of the arithmetic expression, and check that they are numeric.
+// Return the compiled expression, and the type of the result: int64 if all the operands are integers without division,
+// else float64
+func (f *Filter) compileExpression(k string, t reflect.Type) (string, reflect.Type, error) {
+	e, err := parseExpression(k)
+	if err != nil {
+		return "", nil, errors.New(fmt.Sprintf("The Filter key %s isn't a valid expression: %s", k, err))
+	}
+	isFloat, err := f.compileOperands(e, t)
+	if err != nil {
+		return "", nil, err
+	}
+	ck := e.String()
+	if f.expressions == nil {
+		f.expressions = make(map[string]*expression)
+	}
+	f.expressions[ck] = e
+	if isFloat {
+		return ck, float64Type, nil
+	}
+	return ck, int64Type, nil
+}
+
+// Get the arithmetic expression of the compiled key, parsed at compile time.
+// Return false if the key isn't a valid expression
+func (f *Filter) compiledExpression(k string) (*expression, bool) {
+	if e, ok := f.expressions[k]; ok {
+		return e, true
+	}
+	// The expression hasn't been compiled by this filter
+	e, err := parseExpression(k)
+	return e, err == nil
+}
+
+// Compile the keys of the expression. Return true if the result of the expression is a float
+func (f *Filter) compileOperands(e *expression, t reflect.Type) (bool, error) {
+	switch {
+	case e.op != "":
+		lf, err := f.compileOperands(e.left, t)
+		if err != nil {
+			return false, err
+		}
+		rf, err := f.compileOperands(e.right, t)
+		if err != nil {
+			return false, err
+		}
+		return lf || rf || e.op == "/", nil
+	case e.key == "":
+		_, err := strconv.ParseInt(e.literal, 10, 64)
+		return err != nil, nil
+	}
+
+	ck, ct, err := f.compileKey(e.key, t)
+	if err != nil {
+		return false, err
+	}
+	lt := leafType(ct)
+	if !isNumericKind(lt.Kind()) || lt == durationType {
+		return false, errors.New(fmt.Sprintf("The Filter key %s isn't numeric and can't be used in an expression", e.key))
+	}
+	// The integers are evaluated as int64, the 64 bits unsigned integers can overflow it
+	switch lt.Kind() {
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return false, errors.New(fmt.Sprintf("The Filter key %s is a 64 bits unsigned integer and can't be used in an expression", e.key))
+	}
+	e.key = ck
+	return lt.Kind() == reflect.Float32 || lt.Kind() == reflect.Float64, nil
+}
+
+// Result of an arithmetic expression, integer or float
+type number struct {
+	i       int64
+	f       float64
+	isFloat bool
+}
+
+func (n number) float() float64 {
+	if n.isFloat {
+		return n.f
+	}
+	return float64(n.i)
+}
+
+// Evaluate the arithmetic expression on the entry. The integer operations are evaluated natively, with the integer
+// overflow, the division and the float operands lead to a float result.
+// Return false if a key of the expression hasn't exactly one value, like an empty array or a nil pointer
+func (f *Filter) evaluateExpression(e *expression, evs reflect.Value) (number, bool) {
+	switch {
+	case e.op != "":
+		l, ok := f.evaluateExpression(e.left, evs)
+		if !ok {
+			return number{}, false
+		}
+		r, ok := f.evaluateExpression(e.right, evs)
+		if !ok {
+			return number{}, false
+		}
+		return applyOperator(e.op, l, r), true
+	case e.key == "":
+		if i, err := strconv.ParseInt(e.literal, 10, 64); err == nil {
+			return number{i: i}, true
+		}
+		fl, _ := strconv.ParseFloat(e.literal, 64)
+		return number{f: fl, isFloat: true}, true
+	}
+
+	evl := f.findValueInKey(e.key, evs)
+	if len(evl) != 1 {
+		return number{}, false
+	}
+	ev := concreteValue(evl[0])
+	switch ev.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return number{i: ev.Int()}, true
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		// The 64 bits unsigned integers have been rejected at compile time
+		return number{i: int64(ev.Uint())}, true
+	case reflect.Float32, reflect.Float64:
+		return number{f: ev.Float(), isFloat: true}, true
+	}
+	return number{}, false
+}
+
+// Apply the arithmetic operator on the 2 numbers
+func applyOperator(op string, l number, r number) number {
+	if l.isFloat || r.isFloat || op == "/" {
+		lf, rf := l.float(), r.float()
+		switch op {
+		case "+":
+			return number{f: lf + rf, isFloat: true}
+		case "-":
+			return number{f: lf - rf, isFloat: true}
+		case "*":
+			return number{f: lf * rf, isFloat: true}
+		}
+		return number{f: lf / rf, isFloat: true}
+	}
+
+	switch op {
+	case "+":
+		return number{i: l.i + r.i}
+	case "-":
+		return number{i: l.i - r.i}
+	}
+	return number{i: l.i * r.i}
+}
+
+// Find the result of the arithmetic expression on the entry
+// Return no value if a key of the expression hasn't exactly one value
+func (f *Filter) findValueInExpression(k string, evs reflect.Value) []reflect.Value {
+	e, ok := f.compiledExpression(k)
+	if !ok {
+		return []reflect.Value{}
+	}
+	n, ok := f.evaluateExpression(e, evs)
+	switch {
+	case !ok:
+		return []reflect.Value{}
+	case n.isFloat:
+		return []reflect.Value{reflect.ValueOf(n.f)}
+	}
+	return []reflect.Value{reflect.ValueOf(n.i)}
+}
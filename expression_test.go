@@ -0,0 +1,200 @@
+package jsonFilter
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func Test_parseExpression(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    string
+		wantErr bool
+	}{
+		{name: "key", s: "price", want: "price"},
+		{name: "product", s: "price*quantity", want: "(price*quantity)"},
+		{name: "precedence", s: "a+b*c-d/2", want: "((a+(b*c))-(d/2))"},
+		{name: "left to right", s: "a-b-c", want: "((a-b)-c)"},
+		{name: "parenthesis", s: "(a+b)*c", want: "((a+b)*c)"},
+		{name: "negative literal", s: "a*-1.5", want: "(a*-1.5)"},
+		{name: "function and selectors", s: "len(items[0:2])*items[-1].price", want: "(len(items[0:2])*items[-1].price)"},
+		{name: "composed key", s: "end.time-start.time", want: "(end.time-start.time)"},
+		{name: "missing operand", s: "a*", wantErr: true},
+		{name: "negative key", s: "-a", wantErr: true},
+		{name: "missing parenthesis", s: "(a+b", wantErr: true},
+		{name: "extra parenthesis", s: "a+b)", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseExpression(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseExpression() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("parseExpression() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter_compileExpression(t *testing.T) {
+	tests := []struct {
+		name     string
+		k        string
+		wantKey  string
+		wantType reflect.Type
+		wantErr  bool
+	}{
+		{
+			name:     "integer product",
+			k:        "intRoot*2",
+			wantKey:  "(RootInt*2)",
+			wantType: int64Type,
+		},
+		{
+			name:     "float operand",
+			k:        "intRoot+floatRoot",
+			wantKey:  "(RootInt+RootFloat)",
+			wantType: float64Type,
+		},
+		{
+			name:     "division",
+			k:        "len(arrayRootSimple)/intRoot",
+			wantKey:  "(len(RootArraySimple)/RootInt)",
+			wantType: float64Type,
+		},
+		{
+			name:     "float literal",
+			k:        "intRoot*1.5",
+			wantKey:  "(RootInt*1.5)",
+			wantType: float64Type,
+		},
+		{
+			name:    "string operand",
+			k:       "intRoot+stringRoot",
+			wantErr: true,
+		},
+		{
+			name:    "duration operand",
+			k:       "durationRoot*2",
+			wantErr: true,
+		},
+		{
+			name:    "unknown key",
+			k:       "intRoot*unknown",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{
+				options: defaultOption,
+			}
+			gotKey, gotType, err := f.compileExpression(tt.k, reflect.TypeOf(testStruct{}))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("compileExpression() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotKey != tt.wantKey || gotType != tt.wantType {
+				t.Errorf("compileExpression() = %v, %v, want %v, %v", gotKey, gotType, tt.wantKey, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestFilter_compileExpression_unsigned(t *testing.T) {
+	type unsignedStruct struct {
+		Small uint32
+		Size  uint
+		Large uint64
+	}
+	tests := []struct {
+		name    string
+		k       string
+		wantKey string
+		wantErr bool
+	}{
+		{name: "32 bits unsigned integer", k: "Small*2", wantKey: "(Small*2)"},
+		{name: "unsigned integer", k: "Size+1", wantErr: true},
+		{name: "64 bits unsigned integer", k: "Small+Large", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{
+				options: defaultOption,
+			}
+			gotKey, _, err := f.compileExpression(tt.k, reflect.TypeOf(unsignedStruct{}))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("compileExpression() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotKey != tt.wantKey {
+				t.Errorf("compileExpression() = %v, want %v", gotKey, tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestFilter_findValueInExpression(t *testing.T) {
+	entry := reflect.ValueOf(testStruct{
+		RootInt:         7,
+		RootFloat:       0.5,
+		RootArraySimple: []string{"val1", "val2"},
+		RootMapInt:      map[int]string{1: "val1"},
+	})
+	tests := []struct {
+		name string
+		k    string
+		want []interface{}
+	}{
+		{name: "integer", k: "((RootInt*2)-1)", want: []interface{}{int64(13)}},
+		{name: "float", k: "(RootInt*RootFloat)", want: []interface{}{3.5}},
+		{name: "integer division", k: "(RootInt/2)", want: []interface{}{3.5}},
+		{name: "function", k: "(len(RootArraySimple)+len(RootMapInt))", want: []interface{}{int64(3)}},
+		{name: "division by zero", k: "(RootInt/0)", want: []interface{}{math.Inf(1)}},
+		{name: "several values", k: "(RootArraySimple*2)", want: []interface{}{}},
+		{name: "no value", k: "(RootPtrStruct.RootInt+1)", want: []interface{}{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{
+				options: defaultOption,
+			}
+			got := f.findValueInExpression(tt.k, entry)
+			if len(got) != len(tt.want) {
+				t.Fatalf("findValueInExpression() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i].Interface() != tt.want[i] {
+					t.Errorf("findValueInExpression() = %v, want %v", got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFilter_compiledExpression(t *testing.T) {
+	f := &Filter{}
+	if err := f.Init("abs(intRoot-10)=3:intRoot*floatRoot>3", testStruct{}); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	want := []string{"(RootInt-10)", "(RootInt*RootFloat)"}
+	if len(f.expressions) != len(want) {
+		t.Fatalf("compileExpression() expressions = %v, want %v", f.expressions, want)
+	}
+	for _, k := range want {
+		if e, ok := f.expressions[k]; !ok || e.String() != k {
+			t.Errorf("compileExpression() expression %s = %v, want %s", k, e, k)
+		}
+	}
+
+	// The compiled expressions are evaluated without parsing the keys
+	f.expressions["(RootInt*RootFloat)"] = &expression{literal: "4"}
+	got := f.findValueInExpression("(RootInt*RootFloat)", reflect.ValueOf(testStruct{}))
+	if len(got) != 1 || got[0].Interface() != int64(4) {
+		t.Errorf("findValueInExpression() = %v, want [4]", got)
+	}
+}
@@ -0,0 +1,184 @@
+package jsonFilter
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Name of the struct tag controlling the filterability of a struct field, like `filter:"name=price;ops=eq,gt"`
+const filterTagName = "filter"
+
+// Options of the filter struct tag, separated by semicolon
+const (
+	// The struct field can't be used in the filters, like `filter:"-"`
+	hiddenTagOption = "-"
+	// Public name of the struct field in the filter keys, instead of the json tag and the struct field name
+	nameTagOption = "name"
+	// Operators allowed on the struct field, separated by comma, like `filter:"ops=eq,gt"`
+	opsTagOption = "ops"
+)
+
+// Names of the built-in operators in the filter struct tag. The custom operators are named by their token
+const (
+	equalOperatorName       = "eq"
+	notEqualOperatorName    = "ne"
+	greaterThanOperatorName = "gt"
+	lowerThanOperatorName   = "lt"
+	containsOperatorName    = "contains"
+	startsWithOperatorName  = "startswith"
+	endsWithOperatorName    = "endswith"
+)
+
+// Filterability of a struct field, defined in the filter struct tag
+type filterTag struct {
+	// The struct field can't be used in the filters
+	hidden bool
+	// Public name of the struct field. Empty means the json tag or the struct field name
+	name string
+	// Names of the operators allowed on the struct field. Empty means all the operators
+	ops []string
+}
+
+// Parse the filter struct tag of the struct field, like `filter:"-"` or `filter:"name=price;ops=eq,gt"`.
+// Return an error if an option is unknown or empty. The options parsed before the erroneous one are returned
+func parseFilterTag(sf reflect.StructField) (ft filterTag, err error) {
+	tag := sf.Tag.Get(filterTagName)
+	if tag == hiddenTagOption {
+		ft.hidden = true
+		return
+	}
+	if tag == "" {
+		return
+	}
+
+	for _, o := range strings.Split(tag, ";") {
+		kv := strings.SplitN(o, "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return ft, errors.New(fmt.Sprintf("The filter tag option %s of the field %s must be like option=value", o, sf.Name))
+		}
+		switch kv[0] {
+		case nameTagOption:
+			ft.name = kv[1]
+		case opsTagOption:
+			ft.ops = strings.Split(kv[1], ",")
+		default:
+			return ft, errors.New(fmt.Sprintf("The filter tag option %s of the field %s is unknown", kv[0], sf.Name))
+		}
+	}
+	return
+}
+
+// Get the name of the operator in the filter struct tag, like eq for the equal operator
+func (f *Filter) operatorName(op string) string {
+	switch op {
+	case f.options.EqualKeyValueSeparator:
+		return equalOperatorName
+	case f.options.NotEqualKeyValueSeparator:
+		return notEqualOperatorName
+	case f.options.GreaterThanKeyValueSeparator:
+		return greaterThanOperatorName
+	case f.options.LowerThanKeyValueSeparator:
+		return lowerThanOperatorName
+	case f.options.ContainsKeyValueSeparator:
+		return containsOperatorName
+	case f.options.StartsWithKeyValueSeparator:
+		return startsWithOperatorName
+	case f.options.EndsWithKeyValueSeparator:
+		return endsWithOperatorName
+	}
+	return op
+}
+
+// Get the names of the operators used by the filter in the filter struct tag. The interval values of the equal and not
+// equal operators, like [3..6], are compared with their bounds like with the greater than and the lower than operators
+func (f *Filter) operatorNames(kov kov) []string {
+	name := f.operatorName(kov.Operator)
+	if name != equalOperatorName && name != notEqualOperatorName {
+		return []string{name}
+	}
+
+	bounds := map[string]bool{}
+	for _, v := range kov.Values {
+		iv, ok := parseInterval(unescapeReference(v, f.options.FieldReferencePrefix), f.options.RangeValueSeparator)
+		if !ok {
+			bounds[name] = true
+			continue
+		}
+		bounds[greaterThanOperatorName] = bounds[greaterThanOperatorName] || iv.lower != ""
+		bounds[lowerThanOperatorName] = bounds[lowerThanOperatorName] || iv.upper != ""
+	}
+	// Keep a stable order for the error messages
+	var names []string
+	for _, n := range []string{name, greaterThanOperatorName, lowerThanOperatorName} {
+		if bounds[n] {
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+// Check if the operators of the filter are allowed on all the struct fields of the compiled key, according with their
+// filter struct tag.
+// Return an error if a struct field doesn't allow an operator
+func (f *Filter) checkOperator(kov kov, t reflect.Type) error {
+	names := f.operatorNames(kov)
+	for _, sf := range f.fieldsInKey(kov.Key, t) {
+		// The tags have been validated when the key has been compiled
+		ft, _ := parseFilterTag(sf)
+		if len(ft.ops) == 0 {
+			continue
+		}
+		for _, name := range names {
+			allowed := false
+			for _, op := range ft.ops {
+				allowed = allowed || op == name
+			}
+			if !allowed {
+				return errors.New(fmt.Sprintf("The Filter key %s can't be used with the operator %s, the field %s allows only %s", kov.Key, name, sf.Name, strings.Join(ft.ops, ",")))
+			}
+		}
+	}
+	return nil
+}
+
+// Find the struct fields of the compiled key, in the functions and the expressions of the key
+func (f *Filter) fieldsInKey(k string, t reflect.Type) []reflect.StructField {
+	var sfs []reflect.StructField
+	for _, ck := range f.composedKeys(k) {
+		sfs = append(sfs, f.fieldsInComposedKey(ck, t)...)
+	}
+	return sfs
+}
+
+// Find the struct fields of the compiled composed key, with the struct field names. The map entries and the virtual
+// fields aren't struct fields
+func (f *Filter) fieldsInComposedKey(k string, t reflect.Type) []reflect.StructField {
+	var sfs []reflect.StructField
+	for _, p := range strings.Split(k, f.options.ComposedKeySeparator) {
+		// The key part has been validated at compile time
+		name, _, _ := parseKeyPart(p)
+		for t.Kind() == reflect.Slice || t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		switch t.Kind() {
+		case reflect.Map:
+			t = t.Elem()
+			continue
+		case reflect.Struct:
+			if sf, ok := t.FieldByName(name); ok {
+				sfs = append(sfs, sf)
+				t = sf.Type
+				continue
+			}
+			if vf, ok := f.findVirtualField(name, t); ok {
+				t = vf.resultType
+				continue
+			}
+		}
+		return sfs
+	}
+	return sfs
+}
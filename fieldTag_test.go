@@ -0,0 +1,266 @@
+package jsonFilter
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testTagStruct struct {
+	Name     string            `json:"name"`
+	Cost     int               `json:"cost" filter:"-"`
+	Price    int               `json:"price" filter:"ops=eq,gt"`
+	Label    string            `json:"label" filter:"name=title;ops=eq,startswith"`
+	Internal string            `json:"internal" filter:"name=public"`
+	Items    []testTagItem     `json:"items"`
+	Invalid  string            `json:"invalid" filter:"unknown=1"`
+	Tags     map[string]string `json:"tags" filter:"ops=eq"`
+}
+
+type testTagItem struct {
+	Quantity int `json:"quantity" filter:"ops=gt,lt"`
+	Secret   int `json:"secret" filter:"-"`
+}
+
+func Test_parseFilterTag(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     reflect.StructTag
+		want    filterTag
+		wantErr bool
+	}{
+		{
+			name: "no tag",
+			tag:  `json:"name"`,
+		},
+		{
+			name: "hidden",
+			tag:  `filter:"-"`,
+			want: filterTag{hidden: true},
+		},
+		{
+			name: "public name",
+			tag:  `filter:"name=title"`,
+			want: filterTag{name: "title"},
+		},
+		{
+			name: "operators",
+			tag:  `filter:"ops=eq,gt"`,
+			want: filterTag{ops: []string{"eq", "gt"}},
+		},
+		{
+			name: "public name and operators",
+			tag:  `filter:"name=title;ops=eq"`,
+			want: filterTag{name: "title", ops: []string{"eq"}},
+		},
+		{
+			name:    "unknown option",
+			tag:     `filter:"name=title;unknown=1"`,
+			want:    filterTag{name: "title"},
+			wantErr: true,
+		},
+		{
+			name:    "option without value",
+			tag:     `filter:"ops="`,
+			wantErr: true,
+		},
+		{
+			name:    "option without equal",
+			tag:     `filter:"name"`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFilterTag(reflect.StructField{Name: "Field", Tag: tt.tag})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseFilterTag() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseFilterTag() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter_filterTag(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  string
+		want    []kov
+		wantErr bool
+	}{
+		{
+			name:   "field without filter tag",
+			filter: "name=v1",
+			want:   []kov{{Key: "Name", Operator: "=", Values: []string{"v1"}}},
+		},
+		{
+			name:    "hidden field by json name",
+			filter:  "cost=10",
+			wantErr: true,
+		},
+		{
+			name:    "hidden field by struct field name",
+			filter:  "Cost=10",
+			wantErr: true,
+		},
+		{
+			name:    "hidden field in predicate",
+			filter:  "exists(cost)",
+			wantErr: true,
+		},
+		{
+			name:    "hidden field in reference",
+			filter:  "price>$cost",
+			wantErr: true,
+		},
+		{
+			name:    "hidden field in element match",
+			filter:  "items{secret=1}",
+			wantErr: true,
+		},
+		{
+			name:   "allowed operator",
+			filter: "price>10",
+			want:   []kov{{Key: "Price", Operator: ">", Values: []string{"10"}}},
+		},
+		{
+			name:    "not allowed operator",
+			filter:  "price<10",
+			wantErr: true,
+		},
+		{
+			name:    "not allowed operator in function",
+			filter:  "abs(price)<10",
+			wantErr: true,
+		},
+		{
+			name:    "not allowed operator in expression",
+			filter:  "items.quantity*price=10",
+			wantErr: true,
+		},
+		{
+			name:    "not allowed operator in sub struct",
+			filter:  "items.quantity=10",
+			wantErr: true,
+		},
+		{
+			name:    "not allowed operator in element match",
+			filter:  "items{quantity=10}",
+			wantErr: true,
+		},
+		{
+			name:   "allowed operator in element match",
+			filter: "items{quantity>10}",
+			want: []kov{{Key: "Items", Operator: elemMatchOperator, Sub: []kov{
+				{Key: "Quantity", Operator: ">", Values: []string{"10"}},
+			}}},
+		},
+		{
+			name:    "not allowed interval bound",
+			filter:  "price=[3..6]",
+			wantErr: true,
+		},
+		{
+			name:    "not allowed interval bound with not equal",
+			filter:  "price!=(..6]",
+			wantErr: true,
+		},
+		{
+			name:   "allowed interval bound",
+			filter: "price=[3..)",
+			want:   []kov{{Key: "Price", Operator: "=", Values: []string{"[3..)"}}},
+		},
+		{
+			name:   "interval without equal operator",
+			filter: "items{quantity=[1..5]}",
+			want: []kov{{Key: "Items", Operator: elemMatchOperator, Sub: []kov{
+				{Key: "Quantity", Operator: "=", Values: []string{"[1..5]"}},
+			}}},
+		},
+		{
+			name:    "interval mixed with value",
+			filter:  "items{quantity=1,[2..5]}",
+			wantErr: true,
+		},
+		{
+			name:    "not allowed operator in reference",
+			filter:  "items.quantity<$price",
+			wantErr: true,
+		},
+		{
+			name:    "not allowed equal operator in reference",
+			filter:  "price=$items.quantity",
+			wantErr: true,
+		},
+		{
+			name:   "allowed operator in reference",
+			filter: "items.quantity>$price",
+			want:   []kov{{Key: "Items.Quantity", Operator: ">", Values: []string{"$Price"}}},
+		},
+		{
+			name:    "not allowed operator on map",
+			filter:  "tags.env!=prod",
+			wantErr: true,
+		},
+		{
+			name:   "predicate not restricted",
+			filter: "exists(price)",
+			want:   []kov{{Key: "Price", Operator: existsPredicate}},
+		},
+		{
+			name:   "public name",
+			filter: "title^=v1",
+			want:   []kov{{Key: "Label", Operator: "^=", Values: []string{"v1"}}},
+		},
+		{
+			name:    "json name replaced by public name",
+			filter:  "label=v1",
+			wantErr: true,
+		},
+		{
+			name:    "struct field name replaced by public name",
+			filter:  "Internal=v1",
+			wantErr: true,
+		},
+		{
+			name:   "public name without restriction",
+			filter: "public$=v1",
+			want:   []kov{{Key: "Internal", Operator: "$=", Values: []string{"v1"}}},
+		},
+		{
+			name:    "invalid filter tag",
+			filter:  "invalid=v1",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{}
+			err := f.Init(tt.filter, testTagStruct{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Init() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(f.filter, tt.want) {
+				t.Errorf("Init() got = %v, want %v", f.filter, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter_customOperatorTag(t *testing.T) {
+	type entry struct {
+		Sku  string `json:"sku" filter:"ops=~family="`
+		Name string `json:"name" filter:"ops=eq"`
+	}
+	f := &Filter{}
+	if err := f.RegisterOperator("~family=", skuFamily); err != nil {
+		t.Fatalf("RegisterOperator() error = %v", err)
+	}
+	if err := f.Init("sku~family=ABC", entry{}); err != nil {
+		t.Errorf("Init() error = %v, want custom operator allowed", err)
+	}
+	if err := f.Init("name~family=ABC", entry{}); err == nil {
+		t.Errorf("Init() error = nil, want custom operator not allowed")
+	}
+}
@@ -2,4 +2,7 @@ module github.com/guillaumeblaquiere/jsonFilter
 
 go 1.13
 
-require github.com/sirupsen/logrus v1.4.2
+require (
+	github.com/sirupsen/logrus v1.4.2
+	golang.org/x/text v0.3.8
+)
@@ -8,11 +8,99 @@ A filter can be composed to several part:
   - Several filter elements
   - Each filter element have  a tree path into the JSON, name the key, an operator and the value(s) to compare
 
-Each filter element must return OK for keeping the entry value. For this, 4 operators are allowed:
+Each filter element must return OK for keeping the entry value. For this, 7 operators are allowed:
   - The equality, comparable to IN sql clause: at least one value must matches. Default operator is `=`
   - The not equality, comparable to NOT IN sql clause: all values mustn't match. Default operator is `!=`
-  - The Greater Than: only one numeric can be compared. Default operator is `>`
-  - The Lower Than: only one numeric can be compared. Default operator is `<`
+  - The Greater Than: only one value can be compared. Default operator is `>`
+  - The Lower Than: only one value can be compared. Default operator is `<`
+  - The Contains, on string: at least one value must be a substring. Default operator is `*=`
+  - The Starts With, on string: at least one value must be a prefix. Default operator is `^=`
+  - The Ends With, on string: at least one value must be a suffix. Default operator is `$=`
+
+The Greater Than and Lower Than comparisons depend on the type of the key: numeric comparison for numeric values,
+lexicographic comparison for string values and chronological comparison for time values. The lexicographic comparison
+can follow the collation rules of a language by defining it in the options.
+
+The numeric values are compared numerically with all the operators: the filter values can be expressed in any format,
+like 1e+06 or 1000000, and the integer values are compared exactly, even beyond 2^53.
+
+A value starting with '$' is a reference to another key of the same entry, like k1>$k2. The values of the 2 keys are
+compared according with their types, and at least one value of the referenced key must match. The types of the 2 keys
+must be comparable with the operator. A literal value starting with '$' is escaped by doubling it, like k1=$$v1 for the
+value $v1.
+
+The string values are compared case sensitively and byte-wise by default. An operator followed by the insensitive
+modifier, like k1=~v1 or k1>~v2, compares the string values case insensitively (Unicode case folding) and after Unicode
+NFC normalization. The case folding and the normalization can also be enabled for all the filters in the options.
+
+The time values (time.Time) are compared as instant. The filter values can be expressed in these formats
+  - RFC 3339, like 2020-01-31T10:00:00Z. The colons of the time aren't filter separators
+  - Date only, like 2020-01-31. The time is midnight UTC
+  - Relative to the filter application time, like now, now-7d or now+1h30m. Units are s, m, h, d (day) and w (week)
+
+The duration values (time.Duration) are compared on their length. The filter values can be expressed in Go duration
+format, like 30s or 1h30m, in days or weeks, like 7d or 2w, or in nanoseconds, like 30000000000.
+
+The filter keys are searched in the json tags of the struct fields, then in the struct field names. Other struct tags,
+like firestore, can be searched in priority order by defining their names in the options. The keys can also be
+searched case insensitively, if there is no exact match, by enabling the case insensitive keys in the options.
+
+The keys which can be used in the filters can be restricted with the allowed and the denied keys of the options, with
+their struct field names, like RootStruct.SubString. A key allows or denies its sub keys, and the key parts can have
+wildcards, like Root* or *.SubString.
+
+The filter struct tag controls the filterability of a struct field, the options being separated by semicolon:
+  - filter:"-": the field can't be used in the filters, like if it doesn't exist
+  - filter:"name=alias": the field is found by this public name only, instead of its json tag and its name
+  - filter:"ops=eq,gt": only these operators are allowed on the field, among eq, ne, gt, lt, contains, startswith,
+    endswith and the custom operator tokens, in the keys and in the referenced keys. The interval values require gt
+    and lt for their bounds. The predicates and the element match aren't restricted
+
+The types implementing FilterValuer are compared by their canonical value, like a decimal compared as a float. The
+types implementing fmt.Stringer or encoding.TextMarshaler are compared by their string value, like a string enum or a
+decimal struct without exported field, numerically if it's a number. The int enums are compared numerically, and by
+their name with the equality operators, like level=high. The structs with exported fields, the slices and the maps are
+browsed.
+
+The equality and not equality values can be intervals, like k1=[18..65), to express a range on numeric, string, time
+and duration values. The lower bound starts with '[' if it's inclusive or '(' if it's exclusive, the upper bound ends
+with ']' if it's inclusive or ')' if it's exclusive. An empty bound means unbounded, like k1=[18..).
+
+A part of a composed key can select the elements of an array by their position:
+  - Index, like items[0].status. A negative index is counted from the end, like items[-1]
+  - Range, like items[0:3], items[1:] or items[:-1]. The range is truncated to the array length
+
+The key can be wrapped in a function. The function result is compared to the filter values:
+  - len: number of elements of a string (characters), an array or a map, like len(tags)>3 or len(maps)=0
+  - keys: keys of a map, like keys(regions)=eu-west. Each map key is a value of the key, like the values of an array
+  - lower, upper: lower case and upper case of each string value, like lower(status)=open
+  - trim: each string value without leading and trailing spaces, like trim(name)=alice
+  - year: year of each time value, in UTC, like year(createdAt)=2020
+  - abs: absolute value of each numeric or duration value, like abs(delta)<10
+  - round: nearest integer of each numeric value, rounding half away from zero, like round(rating)=4
+
+The key can be an arithmetic expression on numeric keys and literals, with the operators +, -, * and /, like
+price*quantity>100 or (end-start)/60>30. Each key of the expression must have exactly one value for the entry.
+
+By default, in case of several values for a key (array), the equality, greater than and lower than filters match if
+at least one value matches, and the not equality filter matches if all values match (no value is equal). The key can
+be wrapped in a quantifier to define explicitly how many values must match, with all the operators:
+  - any: at least one value must match, like any(tags)!=spam
+  - all: all the values must match, like all(scores)>50. Match if there is no value
+  - none: no value must match, like none(tags)=spam. Match if there is no value
+
+A filter element can be an element match on an array or a map of structs, like k1{k2=v1:k3>v2}. The sub filters,
+separated like the filters, are applied on the keys of the elements, and at least one element must match all of them.
+Without element match, like k1.k2=v1:k1.k3>v2, the sub filters can be matched by different elements.
+
+A filter element can also be a predicate on a key, without operator and values, like exists(k1). The predicates check
+how the key would be rendered by encoding/json, according with the omitempty option of the json tag:
+  - exists: the key is rendered, even with a null value
+  - missing: the key isn't rendered: not existing map entry, nil pointer on the path or empty value with omitempty
+  - null: the key is rendered with a null value (nil pointer, array, map or interface)
+  - notnull: the key is rendered with a value which isn't null
+  - empty: the string, array or map of the key has no element. Missing and nil values are empty
+  - notempty: the string, array or map of the key has at least one element
 
 It's possible to combine operators on the same key, for example k1 < 10 && k1 != 2.
 The same operator on the same key will raise an error.
@@ -23,6 +111,8 @@ The filters are applicable on this list types and structures (and combination po
 	- int
 	- float
 	- bool
+	- time (time.Time)
+	- duration (time.Duration)
   - Complex type
 	- pointer (invisible in JSON result but your structure can include filters)
 	- struct
@@ -98,8 +188,9 @@ import (
 	"errors"
 	"fmt"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/text/language"
 	"reflect"
-	"strconv"
+	"regexp"
 	"strings"
 )
 
@@ -119,29 +210,80 @@ type Options struct {
 	LowerThanKeyValueSeparator string
 	// Character(s) to separate key (filter name)  from values (value to compare) for a not equal comparison. Default is '!='
 	NotEqualKeyValueSeparator string
+	// Character(s) to separate key (filter name)  from values (value to compare) for a substring comparison. Default is '*='
+	ContainsKeyValueSeparator string
+	// Character(s) to separate key (filter name)  from values (value to compare) for a prefix comparison. Default is '^='
+	StartsWithKeyValueSeparator string
+	// Character(s) to separate key (filter name)  from values (value to compare) for a suffix comparison. Default is '$='
+	EndsWithKeyValueSeparator string
 	//  Character(s) to separate values (value to compare). Default is ','
 	ValueSeparator string
 	// Character(s) to separate keys (filters name). Default is ':'
 	KeysSeparator string
 	// Character(s) to separate key part in case of composed key (filter.subfilter) . Default is '.'
 	ComposedKeySeparator string
+	// Character(s) to separate the lower and the upper bounds of an interval value ([lower..upper]). Default is '..'
+	RangeValueSeparator string
+	// Character(s) to start a value which is a reference to another key of the entry ($key). Doubled, they escape a
+	// literal value ($$value). Empty means no reference, the values being literal. Default is '$'
+	FieldReferencePrefix string
+	// Language tag (BCP 47, like 'en' or 'fr-CA') of the collation to use for comparing string values with the greater
+	// than and lower than operators. Empty means a byte-wise comparison. Default is ''
+	Collation string
+	// Character(s) to add after an operator to compare the string values case insensitively and Unicode normalized for
+	// this filter only, like =~. Empty means no modifier, the characters after the operator being part of the value.
+	// Default is '~'
+	InsensitiveModifier string
+	// Compare the string values case insensitively (Unicode case folding) for all the filters. Default is 'false'
+	CaseInsensitive bool
+	// Compare the string values after Unicode NFC normalization for all the filters, like é and e + ◌́. Default is 'false'
+	UnicodeNormalization bool
+	// Names of the struct tags to search the filter keys in, in priority order, like firestore then json. The struct
+	// field names are searched after the tags. Default is 'json'
+	TagNames []string
+	// Search the filter keys case insensitively in the struct tags, the struct field names and the map keys, if there
+	// is no exact match. Default is 'false'
+	CaseInsensitiveKeys bool
+	// Composed keys, with the struct field names, which can be used in the filters, like RootStruct.SubString. A key
+	// allows its sub keys, and the key parts can have wildcards, like Root* or *.SubString. The struct field names are
+	// compared exactly. Empty means all the keys. Default is empty
+	AllowedKeys []string
+	// Composed keys, with the struct field names, which can't be used in the filters, even if they are allowed. Like the
+	// allowed keys, a key denies its sub keys and the key parts can have wildcards. Default is empty
+	DeniedKeys []string
 }
 
 /*
 Filter structure to use for filtering. Init the default value like this
-  filter := jsonFilter.Filter{}
 
+	filter := jsonFilter.Filter{}
 */
 type Filter struct {
 	// Only private fields
 	options *Options
 	filter  []kov
+	// Custom operators registered on the filter, per operator token
+	customOperators map[string]OperatorFunc
+	// Virtual fields registered on the filter, per struct type and per name
+	virtualFields map[reflect.Type]map[string]virtualField
+	// Map keys converted at compile time, per map key type and composed key part
+	mapKeys map[mapKeyPart]reflect.Value
+	// Arithmetic expressions parsed at compile time, per compiled key
+	expressions map[string]*expression
+	// Root type of the filter being compiled, and compiled key of the element match being compiled followed by the
+	// composed key separator, to check the allowed and the denied keys
+	keyRoot   reflect.Type
+	keyPrefix string
 }
 
 type kov struct {
 	Key      string
 	Operator string
 	Values   []string
+	// The string values are compared case insensitively and Unicode normalized, whatever the options
+	Insensitive bool
+	// Sub filters of the element match filter, applied on the same element of the key
+	Sub []kov
 }
 
 // Default option used in case of no specific set.
@@ -151,9 +293,16 @@ var defaultOption = &Options{
 	GreaterThanKeyValueSeparator: ">",
 	LowerThanKeyValueSeparator:   "<",
 	NotEqualKeyValueSeparator:    "!=",
+	ContainsKeyValueSeparator:    "*=",
+	StartsWithKeyValueSeparator:  "^=",
+	EndsWithKeyValueSeparator:    "$=",
 	ValueSeparator:               ",",
 	KeysSeparator:                ":",
 	ComposedKeySeparator:         ".",
+	RangeValueSeparator:          "..",
+	FieldReferencePrefix:         "$",
+	InsensitiveModifier:          "~",
+	TagNames:                     []string{"json"},
 }
 
 /*
@@ -165,21 +314,34 @@ If there is some missing or incorrect value to the defined option, a warning mes
 is replace by the default ones.
 
 To set option:
+
 	filter := jsonFilter.Filter{}
 
 	o := &jsonFilter.Options{
-		MaxDepth:             			4,
-		EqualKeyValueSeparator:    		"=",
-  		GreaterThanKeyValueSeparator: 	">",
-		LowerThanKeyValueSeparator:   	"<",
-		NotEqualKeyValueSeparator:    	"!=",
-		ValueSeparator:       			",",
-		KeysSeparator:        			":",
-		ComposedKeySeparator: 			"->",
+		MaxDepth:                     4,
+		EqualKeyValueSeparator:       "=",
+		GreaterThanKeyValueSeparator: ">",
+		LowerThanKeyValueSeparator:   "<",
+		NotEqualKeyValueSeparator:    "!=",
+		ContainsKeyValueSeparator:    "*=",
+		StartsWithKeyValueSeparator:  "^=",
+		EndsWithKeyValueSeparator:    "$=",
+		ValueSeparator:               ",",
+		KeysSeparator:                ":",
+		ComposedKeySeparator:         "->",
+		RangeValueSeparator:          "..",
+		FieldReferencePrefix:         "$",
+		Collation:                    "en",
+		InsensitiveModifier:          "~",
+		CaseInsensitive:              false,
+		UnicodeNormalization:         true,
+		TagNames:                     []string{"firestore", "json"},
+		CaseInsensitiveKeys:          false,
+		AllowedKeys:                  []string{"RootString", "RootArray.*"},
+		DeniedKeys:                   []string{"RootArray.Cost"},
 	}
 
 	filter.SetOptions(o)
-
 */
 func (f *Filter) SetOptions(o *Options) {
 	if o == nil {
@@ -206,6 +368,18 @@ func (f *Filter) SetOptions(o *Options) {
 		o.NotEqualKeyValueSeparator = defaultOption.NotEqualKeyValueSeparator
 		log.Warnf("NotEqualKeyValueSeparator can't be empty. Option entry ignored, default used %q \n", defaultOption.NotEqualKeyValueSeparator)
 	}
+	if o.ContainsKeyValueSeparator == "" {
+		o.ContainsKeyValueSeparator = defaultOption.ContainsKeyValueSeparator
+		log.Warnf("ContainsKeyValueSeparator can't be empty. Option entry ignored, default used %q \n", defaultOption.ContainsKeyValueSeparator)
+	}
+	if o.StartsWithKeyValueSeparator == "" {
+		o.StartsWithKeyValueSeparator = defaultOption.StartsWithKeyValueSeparator
+		log.Warnf("StartsWithKeyValueSeparator can't be empty. Option entry ignored, default used %q \n", defaultOption.StartsWithKeyValueSeparator)
+	}
+	if o.EndsWithKeyValueSeparator == "" {
+		o.EndsWithKeyValueSeparator = defaultOption.EndsWithKeyValueSeparator
+		log.Warnf("EndsWithKeyValueSeparator can't be empty. Option entry ignored, default used %q \n", defaultOption.EndsWithKeyValueSeparator)
+	}
 	if o.ValueSeparator == "" {
 		o.ValueSeparator = defaultOption.ValueSeparator
 		log.Warnf("ValueSeparator can't be empty. Option entry ignored, default used %q \n", defaultOption.ValueSeparator)
@@ -218,6 +392,18 @@ func (f *Filter) SetOptions(o *Options) {
 		o.ComposedKeySeparator = defaultOption.ComposedKeySeparator
 		log.Warnf("ComposedKeySeparator can't be empty. Option entry ignored, default used %q \n", defaultOption.ComposedKeySeparator)
 	}
+	if o.RangeValueSeparator == "" {
+		o.RangeValueSeparator = defaultOption.RangeValueSeparator
+		log.Warnf("RangeValueSeparator can't be empty. Option entry ignored, default used %q \n", defaultOption.RangeValueSeparator)
+	}
+	if _, err := language.Parse(o.Collation); o.Collation != "" && err != nil {
+		log.Warnf("Collation %q isn't a valid language tag. Option entry ignored, default used %q \n", o.Collation, defaultOption.Collation)
+		o.Collation = defaultOption.Collation
+	}
+	if len(o.TagNames) == 0 {
+		o.TagNames = defaultOption.TagNames
+		log.Warnf("TagNames can't be empty. Option entry ignored, default used %q \n", defaultOption.TagNames)
+	}
 	f.options = o
 }
 
@@ -229,14 +415,35 @@ The filter parsing and compilation are saved in the Filter struct.
 Errors are returned in case of:
   - Duplicated entry in the filter key name for the same operator
   - Violation of filter format:
-    - No values for a key
-    - No key for a filter
-    - More than 1 value for Greater Than and Lower than operator
-    - Not a numeric (float compliant) value for Greater Than and Lower than operator
+  - No values for a key
+  - No key for a filter
+  - More than 1 value for Greater Than and Lower than operator
   - Filter key not exist in the provided interface
-    - Struct field name not match the filter key
-    - Struct json tag not match the filter key
-
+  - Struct field name not match the filter key
+  - Struct tags of the options (json by default) not match the filter key
+  - Several struct fields match the filter key case insensitively, with the case insensitive keys
+  - Filter key browsing deeper than a leaf value, like a time
+  - Time key in an unexported struct field, which can't be read
+  - Invalid array selector, or array selector on a key part which isn't an array
+  - Filter key part not convertible in the map key type
+  - Empty predicate on a key which isn't a string, an array or a map
+  - Unknown function on a key, or function not applicable on the key type
+  - Quantifier which doesn't wrap the whole key
+  - Invalid arithmetic expression, or not numeric or 64 bits unsigned integer key in an expression
+  - Element match on a key which isn't an array or a map of structs
+  - Referenced key, like $key, not existing or not comparable with the key
+  - Invalid filter struct tag, or operator not allowed by the filter struct tag of a field of the key
+  - Key not allowed by the allowed and the denied keys of the options, or invalid key pattern in the options
+  - Filter value not compliant with the key type
+  - Not a time value on time key
+  - Not a duration value on duration key
+  - Not a numeric value on numeric key
+  - Interval value on a key which is neither numeric, string, time nor duration
+  - Greater Than and Lower than operator on a key which is neither numeric nor string
+  - Contains, Starts With and Ends With operator on a key which isn't a string
+
+The custom operators must be registered before the initialization, with RegisterOperator. The virtual fields must be
+registered before the initialization, with RegisterVirtualField.
 */
 func (f *Filter) Init(v string, i interface{}) (err error) {
 	if f.options == nil {
@@ -260,6 +467,7 @@ in the Init method. The entries must be an array.
 Return an array with only the matching entries, else an error is returned.
 
 Cast the return in the array type like this:
+
 	ret, err := filter.ApplyFilter(results)
 	if err != nil {
 		// Perform error handling
@@ -268,7 +476,6 @@ Cast the return in the array type like this:
 	}
 	// Cast here the return
 	results = ret.([]structExample)
-
 */
 func (f *Filter) ApplyFilter(e interface{}) (interface{}, error) {
 
@@ -282,6 +489,9 @@ func (f *Filter) ApplyFilter(e interface{}) (interface{}, error) {
 	//Init ret with the max possible length
 	ret := reflect.MakeSlice(eav.Type(), 0, eav.Len())
 
+	// Comparator of the entry values with the filter values
+	c := newComparator(f.options)
+
 	// Iterate on all e
 	for i := 0; i < eav.Len(); i++ {
 		evs := eav.Index(i)
@@ -290,71 +500,8 @@ func (f *Filter) ApplyFilter(e interface{}) (interface{}, error) {
 
 		// Apply all the filters
 		for _, kov := range f.filter {
-			k := kov.Key
-			// Get the values of the entry for this Filter
-			// Find all possible values per entry in case of composite key
-			evl := f.findValueInComposedKey(k, evs) //entry value list
-
-			// Flag to know if at least one Filter values matches the entry value
-			m := false // match
-
-			// Select the correct operator
-			switch kov.Operator {
-			case f.options.EqualKeyValueSeparator:
-				// Iterate over the matching value of the entry
-				for _, ev := range evl {
-					// Iterate over the filter possible value.
-					for _, v := range kov.Values {
-						// If only one matches, the IN operator is valid
-						if fmt.Sprint(ev) == v {
-							m = true
-							break
-						}
-					}
-					// If the field match stop the loop: At least 1 of the Filter Values have to match (OR condition)
-					if m {
-						break
-					}
-				}
-			case f.options.NotEqualKeyValueSeparator:
-				m = true
-				// Iterate over the matching value of the entry
-				for _, ev := range evl {
-					// Iterate over the filter possible value.
-					for _, v := range kov.Values {
-						// If only one value matches, the NOT IN operator doesn't match: All values must not be in
-						if fmt.Sprint(ev) == v {
-							m = false
-							break
-						}
-					}
-					if !m {
-						break
-					}
-				}
-			case f.options.GreaterThanKeyValueSeparator:
-				for _, ev := range evl {
-					v := kov.Values[0] // always 1 values for greater than operator
-					//Compare only numeric values
-					vf, _ := strconv.ParseFloat(v, 10) // assume that possible thanks to parser check
-					evf, err := strconv.ParseFloat(fmt.Sprint(ev), 10)
-					if err == nil && evf > vf {
-						m = true
-						break
-					}
-				}
-			case f.options.LowerThanKeyValueSeparator:
-				for _, ev := range evl {
-					v := kov.Values[0] // always 1 values for greater than operator
-					//Compare only numeric values
-					vf, _ := strconv.ParseFloat(v, 10) // assume that possible thanks to parser check
-					evf, err := strconv.ParseFloat(fmt.Sprint(ev), 10)
-					if err == nil && evf < vf {
-						m = true
-						break
-					}
-				}
-			}
+			// Flag to know if the entry matches the Filter
+			m := f.matchFilter(kov, evs, c)
 
 			//If any the Filter value matches the entry field value, we don't keep it in the result set
 			// and break the loop because all fields must match. If one fail, stop here
@@ -371,62 +518,180 @@ func (f *Filter) ApplyFilter(e interface{}) (interface{}, error) {
 	return ret.Interface(), nil
 }
 
+// Check if the entry matches the Filter
+func (f *Filter) matchFilter(kov kov, evs reflect.Value, c *comparator) bool {
+	if kov.Insensitive {
+		c = c.insensitive()
+	}
+
+	switch {
+	case kov.Operator == elemMatchOperator:
+		// At least one element of the key must match all the sub filters
+		return f.matchElemMatch(kov, evs, c)
+	case isPredicate(kov.Operator):
+		// The predicates are evaluated on the key, without values
+		return f.matchPredicate(kov, evs)
+	}
+
+	// The quantifier defines how many values of the entry must match the Filter values
+	q, k := getQuantifierAndKey(kov, f.options)
+	// Get the values of the entry for this Filter
+	// Find all possible values per entry in case of composite key
+	evl := f.findValueInKey(k, evs) //entry value list
+	// The references to other keys in the Filter values are found once per entry, like $key
+	refs := f.findReferenceValues(kov, evs)
+	return matchQuantifier(q, evl, func(ev reflect.Value) bool {
+		return f.matchValue(kov, ev, c, refs)
+	})
+}
+
+// Check if the entry value matches the Filter values, according with the operator. The references to other keys in the
+// Filter values are replaced by the values of these keys in the entry
+func (f *Filter) matchValue(kov kov, ev reflect.Value, c *comparator, refs map[string][]reflect.Value) bool {
+	switch kov.Operator {
+	case f.options.EqualKeyValueSeparator:
+		// Iterate over the filter possible value. If only one matches, the IN operator is valid
+		for _, v := range kov.Values {
+			if c.matchOrReference(ev, v, refs) {
+				return true
+			}
+		}
+	case f.options.NotEqualKeyValueSeparator:
+		// Iterate over the filter possible value. If only one value matches, the NOT IN operator doesn't match
+		for _, v := range kov.Values {
+			if c.matchOrReference(ev, v, refs) {
+				return false
+			}
+		}
+		return true
+	case f.options.GreaterThanKeyValueSeparator:
+		// always 1 values for greater than operator. Not comparable values don't match
+		return c.compareOrReference(ev, kov.Values[0], refs, func(r int) bool { return r > 0 })
+	case f.options.LowerThanKeyValueSeparator:
+		// always 1 values for lower than operator. Not comparable values don't match
+		return c.compareOrReference(ev, kov.Values[0], refs, func(r int) bool { return r < 0 })
+	case f.options.ContainsKeyValueSeparator:
+		return c.matchString(ev, c.expandReferences(kov.Values, refs), strings.Contains)
+	case f.options.StartsWithKeyValueSeparator:
+		return c.matchString(ev, c.expandReferences(kov.Values, refs), strings.HasPrefix)
+	case f.options.EndsWithKeyValueSeparator:
+		return c.matchString(ev, c.expandReferences(kov.Values, refs), strings.HasSuffix)
+	}
+	// The custom operators receive the Filter values as is
+	if fn, ok := f.customOperators[kov.Operator]; ok {
+		return fn(ev, kov.Values)
+	}
+	return false
+}
+
 // Find all values (leaf value) associated with a composed key (filter name).
 // Return always an array of values in case of search in sub elements which are an array of structs
 func (f *Filter) findValueInComposedKey(k string, evs reflect.Value) []reflect.Value {
+	r := make([]reflect.Value, 0) //result
+	for _, cv := range f.findContainerInComposedKey(k, evs) {
+		r = appendLeafValue(r, cv.value)
+	}
+	return r
+}
+
+// Value found at the end of a composed key, as is in the struct: the pointers and the arrays are kept
+type containerValue struct {
+	value reflect.Value
+	// The value is a struct field with the json omitempty option
+	omitEmpty bool
+}
+
+// Find all values associated with a composed key (filter name), without browsing the values found at the end of the key.
+// The browsed values which lead to nil pointer or to not existing map entry are ignored.
+// Return always an array of values in case of search in sub elements which are an array of structs
+func (f *Filter) findContainerInComposedKey(k string, evs reflect.Value) []containerValue {
 	kp := strings.Split(k, f.options.ComposedKeySeparator) //key p
 	vs := []reflect.Value{evs}                             // values
+	var cvs []containerValue                               // container values
 
 	//Scan all p of the composed key, going deeper and deeper
-	for _, p := range kp {
+	for i, p := range kp {
 		r := make([]reflect.Value, 0) //result
+		cvs = make([]containerValue, 0)
+		// The key part has been validated at compile time
+		name, sels, _ := parseKeyPart(p)
 
 		// Scan recursively all sub values found
 		for _, v := range vs {
 
 			var res reflect.Value
+			omitEmpty := false
 			// If the current element is a map
 			if v.Kind() == reflect.Map {
-				// search the matching key in the value list
-				foundEntry := false
-				for _, val := range v.MapKeys() {
-					if fmt.Sprint(val) == p {
-						res = v.MapIndex(val)
-						foundEntry = true
-						break //only one entry in the map key list
-					}
-				}
-				if !foundEntry {
+				// get the matching entry of the map, the key part being converted in the map key type
+				res = f.findMapEntry(v, name)
+				if !res.IsValid() {
 					//If no entry match the key of the map key list, continue to the next value, forget this p of the tree
 					continue
 				}
-			} else { // if not, scan the structure
-				res = v.FieldByName(p)
+			} else if sf, ok := v.Type().FieldByName(name); ok { // if not, scan the structure
+				res = v.FieldByName(name)
+				omitEmpty = hasJsonOption(sf, "omitempty")
+			} else if vf, ok := f.findVirtualField(name, v.Type()); ok { // else compute the virtual field
+				if res, ok = vf.value(v); !ok {
+					continue
+				}
+			} else {
+				continue
 			}
 
-			//In case of pointer
-			if res.Kind() == reflect.Ptr {
-				//If the pointer lead to nil value
-				if res.Pointer() == 0 {
+			// Select the elements of the array, like items[0]. The selected elements are always rendered
+			if len(sels) > 0 {
+				var ok bool
+				if res, ok = applySelectors(res, sels); !ok {
 					continue
 				}
-				res = res.Elem()
+				omitEmpty = false
 			}
 
-			// In case of array found, add all the matching values to the result (or next value th scan if not the leaf)
-			if res.Kind() == reflect.Slice {
-				r = extractValueFromSlice(r, res)
+			// Keep the end of the composed key as is, else browse the value
+			if i == len(kp)-1 {
+				cvs = append(cvs, containerValue{value: res, omitEmpty: omitEmpty})
 			} else {
-				r = append(r, res)
+				r = appendLeafValue(r, res)
 			}
-
 		}
 		vs = r
 	}
-	return vs
+	return cvs
+}
+
+// Append the value to the list. In case of pointer, the pointed value is appended, except if the pointer is nil.
+// In case of array, all the values are appended (or next value to scan if not the leaf)
+func appendLeafValue(r []reflect.Value, res reflect.Value) []reflect.Value {
+	//In case of pointer
+	if res.Kind() == reflect.Ptr {
+		//If the pointer lead to nil value
+		if res.Pointer() == 0 {
+			return r
+		}
+		res = res.Elem()
+	}
+
+	// In case of array found, add all the matching values to the result (or next value th scan if not the leaf)
+	if res.Kind() == reflect.Slice {
+		return extractValueFromSlice(r, res)
+	}
+	return append(r, res)
+}
+
+// Check if the json tag of the struct field has the option, like omitempty
+func hasJsonOption(sf reflect.StructField, o string) bool {
+	opts := strings.Split(sf.Tag.Get("json"), ",")
+	for _, opt := range opts[1:] {
+		if opt == o {
+			return true
+		}
+	}
+	return false
 }
 
-//Recursive loop for getting all the values from a Tensor (array of N dimension)
+// Recursive loop for getting all the values from a Tensor (array of N dimension)
 func extractValueFromSlice(r []reflect.Value, v reflect.Value) []reflect.Value {
 	for i := 0; i < v.Len(); i++ {
 		if v.Index(i).Kind() == reflect.Slice {
@@ -452,20 +717,35 @@ func extractValueFromSlice(r []reflect.Value, v reflect.Value) []reflect.Value {
 // Filter default option pattern is key1=value1,value2:key1!=value:key2=value3,value4
 func (f *Filter) parseFilter(filterInput string) (kovs []kov, err error) {
 	kovs = []kov{}
-	fts := strings.Split(filterInput, f.options.KeysSeparator)
+	fts := splitOutsideBrackets(filterInput, f.options.KeysSeparator)
 
 	// Parse all fts found
 	for _, ft := range fts {
-
-		kv, op := f.getFilterAndValue(ft)
-
-		// If there isn't values part, it's an error
-		if !isKeyValuesValidPair(kv) {
+		var k, op string
+		var v []string
+		var sk []kov // sub kovs
+		var ins bool // insensitive
+
+		if ek, sub, ok := f.getElemMatchAndKey(ft); ok {
+			// An element match has sub filters instead of values, like array{a=1:b=2}
+			k, op = ek, elemMatchOperator
+			if sk, err = f.parseFilter(sub); err != nil {
+				return nil, err
+			}
+		} else if kv, o := f.getFilterAndValue(ft); isKeyValuesValidPair(kv) {
+			k = kv[0]
+			// The operator can have the insensitive modifier, like =~
+			op, ins = f.getOperatorAndModifier(o)
+			// extract the values
+			v = strings.Split(kv[1], f.options.ValueSeparator)
+		} else if pk, p, ok := getPredicateAndKey(ft); ok {
+			// A predicate has no values, like exists(key)
+			k, op = pk, p
+		} else {
+			// If there isn't values part, it's an error
 			return nil, errors.New(fmt.Sprintf("No values defined for the key %s ft", ft))
 		}
 
-		k := kv[0]
-
 		// Check if key is not empty
 		if k == "" {
 			return nil, errors.New("No filter key")
@@ -476,61 +756,130 @@ func (f *Filter) parseFilter(filterInput string) (kovs []kov, err error) {
 			return nil, errors.New(fmt.Sprintf("The Filter key %s doen't match the max depth key set to %d", k, f.options.MaxDepth))
 		}
 
-		// Check if the key with the same operator has been already set in the map
+		// Check if the key with the same operator has been already set in the map. Several element matches can be
+		// applied on the same key
 		for _, kov := range kovs {
-			if kov.Key == k && kov.Operator == op {
+			if kov.Key == k && kov.Operator == op && op != elemMatchOperator {
 				return nil, errors.New(fmt.Sprintf("The key %s already for the operator %s exist in the Filter field", k, op))
 			}
 		}
 
-		// extract the values and set them to the map
-		v := strings.Split(kv[1], f.options.ValueSeparator)
-
 		if op == f.options.GreaterThanKeyValueSeparator || op == f.options.LowerThanKeyValueSeparator {
 			if len(v) > 1 {
 				return nil, errors.New("the Filter 'greater than' and 'lower than' must have exactly 1 value")
 			}
-			if _, err := strconv.ParseFloat(v[0], 10); err != nil {
-				return nil, errors.New(fmt.Sprintf("the Filter 'greater than' and 'lower than' must have a numeric value. Here %v", filterInput[0]))
-			}
 		}
 
 		kovs = append(kovs, kov{
-			Key:      k,
-			Operator: op,
-			Values:   v,
+			Key:         k,
+			Operator:    op,
+			Values:      v,
+			Insensitive: ins,
+			Sub:         sk,
 		})
 	}
 	return
 }
 
+// RFC 3339 time values, which contain colons, like 2020-01-31T10:00:00Z or 2020-01-31T11:00:00.5+01:00
+var timeLiteral = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})?`)
+
+// Split the string around the separator, except inside brackets, braces and parenthesis, like in array{a=1:b=2}, and
+// inside the RFC 3339 time values, like in t>2020-01-31T10:00:00Z:k=v.
+// If they aren't balanced, the string is split around all the separators, except inside the time values
+func splitOutsideBrackets(s string, sep string) []string {
+	if r, ok := splitOutside(s, sep, true); ok {
+		return r
+	}
+	r, _ := splitOutside(s, sep, false)
+	return r
+}
+
+// Split the string around the separator, except inside the time values and, if requested, inside the brackets.
+// Return false if the brackets aren't balanced
+func splitOutside(s string, sep string, brackets bool) ([]string, bool) {
+	r := make([]string, 0) //result
+	depth, start := 0, 0
+	tls := timeLiteral.FindAllStringIndex(s, -1)
+	for i := 0; i < len(s); i++ {
+		// The time values are kept as is
+		for len(tls) > 0 && tls[0][1] <= i {
+			tls = tls[1:]
+		}
+		if len(tls) > 0 && i >= tls[0][0] {
+			i = tls[0][1] - 1
+			continue
+		}
+		switch {
+		case !brackets:
+		case strings.IndexByte("{[(", s[i]) >= 0:
+			depth++
+		case strings.IndexByte("}])", s[i]) >= 0:
+			depth--
+		}
+		if depth < 0 {
+			return nil, false
+		}
+		if depth == 0 && strings.HasPrefix(s[i:], sep) {
+			r = append(r, s[start:i])
+			start = i + len(sep)
+			i = start - 1
+		}
+	}
+	return append(r, s[start:]), depth == 0
+}
+
 // Get the key and the values of the filters by testing possible operators
 // return an empty array if any separator matches
 // In case of 2 separators work when splitting the filter, we keep only the longest separator
 // Example: in case of = and != both will split on =, but we only keep != because it's the longest
 func (f *Filter) getFilterAndValue(filter string) (fkvs []string, op string) {
-
-	if fkv := strings.Split(filter, f.options.EqualKeyValueSeparator); isKeyValuesValidPair(fkv) && len(op) < len(f.options.EqualKeyValueSeparator) {
-		fkvs = fkv
-		op = f.options.EqualKeyValueSeparator
+	for _, o := range f.operators() {
+		if fkv := strings.Split(filter, o); isKeyValuesValidPair(fkv) && len(op) < len(o) {
+			fkvs = fkv
+			op = o
+		}
 	}
+	return
+}
 
-	if fkv := strings.Split(filter, f.options.NotEqualKeyValueSeparator); isKeyValuesValidPair(fkv) && len(op) < len(f.options.NotEqualKeyValueSeparator) {
-		fkvs = fkv
-		op = f.options.NotEqualKeyValueSeparator
+// Get the built-in operators, without modifier
+func (f *Filter) builtinOperators() []string {
+	return []string{
+		f.options.EqualKeyValueSeparator,
+		f.options.NotEqualKeyValueSeparator,
+		f.options.GreaterThanKeyValueSeparator,
+		f.options.LowerThanKeyValueSeparator,
+		f.options.ContainsKeyValueSeparator,
+		f.options.StartsWithKeyValueSeparator,
+		f.options.EndsWithKeyValueSeparator,
 	}
+}
 
-	if fkv := strings.Split(filter, f.options.GreaterThanKeyValueSeparator); isKeyValuesValidPair(fkv) && len(op) < len(f.options.GreaterThanKeyValueSeparator) {
-		fkvs = fkv
-		op = f.options.GreaterThanKeyValueSeparator
+// Get all the operators: the built-in operators, without and with the insensitive modifier, and the custom operators.
+// Without insensitive modifier in the options, there is no operator with modifier
+func (f *Filter) operators() []string {
+	ops := f.builtinOperators()
+	if f.options.InsensitiveModifier != "" {
+		// The range is evaluated once, on the operators without modifier
+		for _, o := range ops {
+			ops = append(ops, o+f.options.InsensitiveModifier)
+		}
 	}
+	return append(ops, f.customOperatorTokens()...)
+}
 
-	if fkv := strings.Split(filter, f.options.LowerThanKeyValueSeparator); isKeyValuesValidPair(fkv) && len(op) < len(f.options.LowerThanKeyValueSeparator) {
-		fkvs = fkv
-		op = f.options.LowerThanKeyValueSeparator
+// Get the operator without the insensitive modifier, and true if the modifier was present, like =~
+func (f *Filter) getOperatorAndModifier(op string) (string, bool) {
+	if f.options.InsensitiveModifier == "" {
+		return op, false
 	}
-
-	return
+	for _, o := range f.builtinOperators() {
+		if op == o+f.options.InsensitiveModifier {
+			return o, true
+		}
+	}
+	return op, false
 }
 
 func isKeyValuesValidPair(fkvs []string) bool {
@@ -538,80 +887,270 @@ func isKeyValuesValidPair(fkvs []string) bool {
 }
 
 // Find the struct field name in relation with the Filter name provided in the query
-// The search is performed in the json tag of the struct field and on the struct field name in case of missing tag;
+// The search is performed in the struct tags of the options (json by default), in priority order, and on the struct
+// field name in case of missing tag;
+// When found, the values are checked against the type of the leaf value of the key
 func (f *Filter) compileFilter(kovs []kov, t reflect.Type) (err error) {
-	f.filter = []kov{}
+	f.mapKeys = nil
+	f.expressions = nil
+	// The keys must be allowed by the options, with their struct field names, when they are compiled. The filters
+	// before the denied one are kept
+	f.keyRoot, f.keyPrefix = t, ""
+	f.filter, err = f.compileFilters(kovs, t)
+	f.keyRoot = nil
+	return
+}
+
+// Compile the filters against the type. In case of error, the filters compiled before the erroneous one are returned
+func (f *Filter) compileFilters(kovs []kov, t reflect.Type) (ckovs []kov, err error) {
+	ckovs = []kov{}
 
 	//for all  filters, search is a struct field name match with it
 	for _, kov := range kovs {
-		k := kov.Key
-		ck := "" // composed key
-		ckp := strings.Split(k, f.options.ComposedKeySeparator)
-		ct := t //current type
+		var ct reflect.Type // type of the key
+		switch {
+		case kov.Operator == elemMatchOperator:
+			// The sub filters of the element match are compiled against the type of the key elements
+			kov.Key, ct, kov.Sub, err = f.compileElemMatch(kov.Key, kov.Sub, t)
+		case isPredicate(kov.Operator):
+			// The predicates are evaluated on a composed key only, the other filters can use functions on the key
+			kov.Key, ct, err = f.compileComposedKey(kov.Key, t)
+		default:
+			kov.Key, ct, err = f.compileQuantifiedKey(kov.Key, t)
+			if err == nil {
+				// The operators can be restricted on the struct fields of the key, with the filter struct tag
+				err = f.checkOperator(kov, t)
+			}
+		}
+		if err != nil {
+			return
+		}
 
-		// validate the struct field name according with the key composition. Going deeper and deeper
-		for i, p := range ckp {
-			var cp string //composed part
+		// The references to other keys in the values are compiled like the keys, like $key
+		if kov.Values, err = f.compileReferences(kov, ct, t); err != nil {
+			return
+		}
+		// Check the values against the leaf type of the key
+		if err = f.checkValues(kov, leafType(ct)); err != nil {
+			return
+		}
+		// The empty predicates are evaluated on the container, before flattening it
+		if (kov.Operator == emptyPredicate || kov.Operator == notEmptyPredicate) && !isContainerType(ct) {
+			err = errors.New(fmt.Sprintf("The Filter key %s isn't a string, an array or a map and can't be checked by the predicate %s", kov.Key, kov.Operator))
+			return
+		}
+		ckovs = append(ckovs, kov)
+	}
+	return
+}
+
+// Find the struct field names in relation with the composed key provided in the query.
+// Return the composed key with the struct field names, and the type of the struct field (or map value) at the end of
+// the composed key
+func (f *Filter) compileComposedKey(k string, t reflect.Type) (ck string, ct reflect.Type, err error) {
+	ckp := strings.Split(k, f.options.ComposedKeySeparator)
+	ct = t              //current type
+	unexported := false // the key goes through an unexported struct field
+
+	// validate the struct field name according with the key composition. Going deeper and deeper
+	for i, p := range ckp {
+		var cp string //composed part
+
+		// The part can have array selectors, like items[0]
+		name, sels, err := parseKeyPart(p)
+		if err != nil {
+			return "", nil, err
+		}
 
-			// If array, loop on it to get the element contained in the tensor (Array of N dimension)
-			for ct.Kind() == reflect.Slice {
+		// If array, loop on it to get the element contained in the tensor (Array of N dimension)
+		for ct.Kind() == reflect.Slice {
+			ct = ct.Elem()
+			//In case of ptr
+			if ct.Kind() == reflect.Ptr {
 				ct = ct.Elem()
-				//In case of ptr
-				if ct.Kind() == reflect.Ptr {
-					ct = ct.Elem()
-				}
 			}
-			//if map, keep the key as is
-			if ct.Kind() == reflect.Map {
-				cp = p
+		}
+		// A leaf value (like a time or a string) can't be browsed deeper
+		if isLeafType(ct) || (leafType(ct).Kind() != reflect.Struct && leafType(ct).Kind() != reflect.Map) {
+			return "", nil, errors.New(fmt.Sprintf("The Filter key %s can't be browsed deeper than %s", k, ck))
+		}
+		//if map, keep the key as is
+		if ct.Kind() == reflect.Map {
+			// The key part must be convertible in the map key type
+			if err = f.compileMapKey(name, ct.Key()); err != nil {
+				return "", nil, errors.New(fmt.Sprintf("The Filter key %s can't be found in the map: %s", k, err))
+			}
+			cp = name
+			ct = ct.Elem()
+			//In case of ptr
+			if ct.Kind() == reflect.Ptr {
 				ct = ct.Elem()
-				//In case of ptr
-				if ct.Kind() == reflect.Ptr {
-					ct = ct.Elem()
-				}
-			} else { // look into the structure
+			}
+		} else { // look into the structure
 
-				fs := foundFieldInStruct(p, ct)
-				// If no match found, raise an error
-				if fs == nil {
-					log.Debugf("The Filter key %s not exist in the type %s", p, t.Name())
-					return errors.New(fmt.Sprintf("The Filter key %s not exist in the returned object", ck+" "+p))
+			fs, err := f.foundFieldInStruct(name, ct)
+			if err != nil {
+				return "", nil, err
+			}
+			vf, isVirtual := f.findVirtualField(name, ct)
+			switch {
+			case fs != nil:
+				if _, err = parseFilterTag(*fs); err != nil {
+					return "", nil, err
 				}
 				ct = fs.Type
+				unexported = unexported || fs.PkgPath != ""
 
 				// If it's not the root element of the composed key, add a separator the the filter name
 				cp = fs.Name
+			case isVirtual:
+				// If no struct field match, the virtual field is kept as is in the key
+				ct = vf.resultType
+				cp = name
+			default:
+				// If no match found, raise an error
+				log.Debugf("The Filter key %s not exist in the type %s", name, t.Name())
+				return "", nil, keyNotExistError(k)
 			}
-			//Add a composed separator if it's not the root p
-			if i != 0 {
-				ck += f.options.ComposedKeySeparator
+		}
+
+		// Apply the array selectors on the type, and keep them as is in the key
+		if len(sels) > 0 {
+			if ct, err = compileSelectors(ct, sels); err != nil {
+				return "", nil, errors.New(fmt.Sprintf("The Filter key %s can't be selected: %s", k, err))
 			}
-			ck += cp
+			cp += p[len(name):]
+		}
+		//Add a composed separator if it's not the root p
+		if i != 0 {
+			ck += f.options.ComposedKeySeparator
 		}
-		kov.Key = ck
-		f.filter = append(f.filter, kov)
+		ck += cp
+	}
+	// The key must be allowed before its type is checked, to not reveal it
+	if err = f.checkKeyAccess(ck, k); err != nil {
+		return "", nil, err
+	}
+	// The time values of the unexported struct fields can't be read
+	if unexported && leafType(ct) == timeType {
+		return "", nil, errors.New(fmt.Sprintf("The Filter key %s is an unexported time field and can't be compared", k))
 	}
 	return
 }
 
-// Return the structField found according with the filter key name and the type to scan.
-// Return nil if nothing found in the type.
-func foundFieldInStruct(k string, t reflect.Type) *reflect.StructField {
+// Check if the values of the filter are compliant with the leaf type of the key, according with the operator
+func (f *Filter) checkValues(kov kov, t reflect.Type) error {
+	switch kov.Operator {
+	case f.options.EqualKeyValueSeparator, f.options.NotEqualKeyValueSeparator:
+		for _, v := range kov.Values {
+			if f.isReference(v) {
+				continue
+			}
+			if err := checkMatchValue(unescapeReference(v, f.options.FieldReferencePrefix), f.options.RangeValueSeparator, t); err != nil {
+				return errors.New(fmt.Sprintf("The Filter key %s can't be compared: %s", kov.Key, err))
+			}
+		}
+	case f.options.GreaterThanKeyValueSeparator, f.options.LowerThanKeyValueSeparator:
+		if f.isReference(kov.Values[0]) {
+			return nil
+		}
+		if err := checkRangeValue(unescapeReference(kov.Values[0], f.options.FieldReferencePrefix), t); err != nil {
+			return errors.New(fmt.Sprintf("The Filter key %s can't be compared: %s", kov.Key, err))
+		}
+	case f.options.ContainsKeyValueSeparator, f.options.StartsWithKeyValueSeparator, f.options.EndsWithKeyValueSeparator:
+		if t.Kind() != reflect.String && t.Kind() != reflect.Interface {
+			return errors.New(fmt.Sprintf("The Filter key %s isn't a string and can't be compared with the operator %s", kov.Key, kov.Operator))
+		}
+	}
+	return nil
+}
+
+// Check if the type is a leaf even if it's a structure, like time or a type with a canonical value (FilterValuer).
+func isLeafType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	_, ok := canonicalType(t)
+	return t == timeType || ok
+}
+
+// Get the type of the leaf values. Array (tensor) and pointer are invisible in the processing, get their element type.
+// The types with a canonical value (FilterValuer) are replaced by the type of their canonical value
+func leafType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Slice || t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	t, _ = canonicalType(t)
+	return t
+}
+
+// Return the structField found according with the filter key name and the type to scan. The key is searched in the
+// struct tags of the options, in priority order, then in the struct field names. With the case insensitive keys, the
+// key is searched case insensitively if there is no exact match.
+// Return nil if nothing found in the type, and an error if several fields match the key case insensitively.
+func (f *Filter) foundFieldInStruct(k string, t reflect.Type) (*reflect.StructField, error) {
 	ct := t // current type
 	//In case of ptr
 	if t.Kind() == reflect.Ptr {
 		ct = t.Elem()
 	}
 
-	for i := 0; i < ct.NumField(); i++ {
-		f := ct.Field(i)
+	// Get the names of all the fields, in priority order, before searching the key name by name
+	names := make([][]string, ct.NumField())
+	for i := range names {
+		names[i] = f.fieldNames(ct.Field(i))
+	}
+	for p := 0; p <= len(f.options.TagNames); p++ {
+		for i, ns := range names {
+			// on each fields, check if the Filter can be applied
+			if p < len(ns) && ns[p] != "" && ns[p] == k {
+				// When found,add it to the map and go to the next Filter f
+				sf := ct.Field(i)
+				return &sf, nil
+			}
+		}
+	}
+	if !f.options.CaseInsensitiveKeys {
+		return nil, nil
+	}
+
+	// All the fields matching case insensitively are searched, whatever the name priority, to detect the ambiguity
+	var fs *reflect.StructField
+	for i, ns := range names {
+		for _, n := range ns {
+			if n == "" || !strings.EqualFold(n, k) {
+				continue
+			}
+			if fs != nil && fs.Index[0] != i {
+				return nil, errors.New(fmt.Sprintf("The Filter key %s is ambiguous, it matches the fields %s and %s case insensitively", k, fs.Name, ct.Field(i).Name))
+			}
+			sf := ct.Field(i)
+			fs = &sf
+		}
+	}
+	return fs, nil
+}
+
+// Get the names of the struct field in the filter keys, in priority order: the name in each struct tag of the options,
+// empty if the tag doesn't define a name, then the struct field name.
+// The public name of the filter struct tag replaces all the names, the hidden fields have no name
+func (f *Filter) fieldNames(sf reflect.StructField) []string {
+	ft, _ := parseFilterTag(sf)
+	switch {
+	case ft.hidden:
+		return nil
+	case ft.name != "":
+		return []string{ft.name}
+	}
 
-		// on each fields, check if the Filter can be applied
-		if strings.Contains(f.Tag.Get("json"), k) ||
-			f.Name == k {
-			// When found,add it to the map and go to the next Filter f
-			return &f
+	names := make([]string, 0, len(f.options.TagNames)+1)
+	for _, tn := range f.options.TagNames {
+		// The tag name is before the tag options, like json:"name,omitempty". A "-" name means no name
+		n := strings.Split(sf.Tag.Get(tn), ",")[0]
+		if n == "-" {
+			n = ""
 		}
+		names = append(names, n)
 	}
-	return nil
+	return append(names, sf.Name)
 }
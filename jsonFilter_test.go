@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestFilter_ApplyFilter(t *testing.T) {
@@ -189,6 +190,36 @@ func TestFilter_ApplyFilter(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Minimal filter equals with numeric format",
+			fields: fields{
+				options: defaultOption,
+				filter: []kov{
+					{
+						Key:      "RootInt",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"1e+06"},
+					},
+				},
+			},
+			args: args{entries: []testStruct{
+				{
+					RootString: "value1",
+					RootInt:    1000000,
+				},
+				{
+					RootString: "value2",
+					RootInt:    1000001,
+				},
+			}},
+			want: []testStruct{
+				{
+					RootString: "value1",
+					RootInt:    1000000,
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "Minimal filter greater than negative",
 			fields: fields{
@@ -220,373 +251,1743 @@ func TestFilter_ApplyFilter(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name: "Composite filter",
+			name: "Minimal filter greater than string",
 			fields: fields{
 				options: defaultOption,
 				filter: []kov{
 					{
-						Key:      "RootArray.SubString",
-						Operator: defaultOption.EqualKeyValueSeparator,
-						Values:   []string{"string1"},
+						Key:      "RootString",
+						Operator: defaultOption.GreaterThanKeyValueSeparator,
+						Values:   []string{"M"},
 					},
 				},
 			},
 			args: args{entries: []testStruct{
 				{
-					RootString: "value1",
-					RootInt:    0,
-					RootFloat:  0,
-					RootBool:   false,
-					RootArray: []SubStruct{
-						{SubString: "string1"},
-					},
-					RootStruct: SubStruct{},
+					RootString: "Alice",
 				},
 				{
-					RootString: "value2",
-					RootInt:    0,
-					RootFloat:  0,
-					RootBool:   false,
-					RootArray: []SubStruct{
-						{SubString: "string2"},
-					},
-					RootStruct: SubStruct{},
+					RootString: "Zoe",
 				},
+			}},
+			want: []testStruct{
 				{
-					RootString: "value3",
-					RootInt:    0,
-					RootFloat:  0,
-					RootBool:   false,
-					RootArray: []SubStruct{
-						{SubString: "string1"},
-						{SubString: "string2"},
+					RootString: "Zoe",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Minimal filter lower than string with collation",
+			fields: fields{
+				options: &Options{
+					EqualKeyValueSeparator:       "=",
+					GreaterThanKeyValueSeparator: ">",
+					LowerThanKeyValueSeparator:   "<",
+					NotEqualKeyValueSeparator:    "!=",
+					ValueSeparator:               ",",
+					KeysSeparator:                ":",
+					ComposedKeySeparator:         ".",
+					Collation:                    "en",
+				},
+				filter: []kov{
+					{
+						Key:      "RootString",
+						Operator: defaultOption.LowerThanKeyValueSeparator,
+						Values:   []string{"Banana"},
 					},
-					RootStruct: SubStruct{},
+				},
+			},
+			args: args{entries: []testStruct{
+				{
+					RootString: "apple",
+				},
+				{
+					RootString: "cherry",
 				},
 			}},
 			want: []testStruct{
 				{
-					RootString: "value1",
-					RootInt:    0,
-					RootFloat:  0,
-					RootBool:   false,
-					RootArray: []SubStruct{
-						{SubString: "string1"},
+					RootString: "apple",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Minimal filter greater than time",
+			fields: fields{
+				options: defaultOption,
+				filter: []kov{
+					{
+						Key:      "RootTime",
+						Operator: defaultOption.GreaterThanKeyValueSeparator,
+						Values:   []string{"2020-01-31"},
 					},
-					RootStruct: SubStruct{},
 				},
+			},
+			args: args{entries: []testStruct{
 				{
-					RootString: "value3",
-					RootInt:    0,
-					RootFloat:  0,
-					RootBool:   false,
-					RootArray: []SubStruct{
-						{SubString: "string1"},
-						{SubString: "string2"},
-					},
-					RootStruct: SubStruct{},
+					RootString: "value1",
+					RootTime:   time.Date(2020, 1, 30, 0, 0, 0, 0, time.UTC),
+				},
+				{
+					RootString: "value2",
+					RootTime:   time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC),
+				},
+			}},
+			want: []testStruct{
+				{
+					RootString: "value2",
+					RootTime:   time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC),
 				},
 			},
 			wantErr: false,
 		},
 		{
-			name: "Minimal filter",
+			name: "Minimal filter greater than duration",
 			fields: fields{
 				options: defaultOption,
 				filter: []kov{
 					{
-						Key:      "RootString",
-						Operator: defaultOption.EqualKeyValueSeparator,
-						Values:   []string{"value1"},
+						Key:      "RootDuration",
+						Operator: defaultOption.GreaterThanKeyValueSeparator,
+						Values:   []string{"1m30s"},
 					},
 				},
 			},
-			args: args{entries: testStruct{
-				RootString: "value1",
-				RootInt:    0,
-				RootFloat:  0,
-				RootBool:   false,
-				RootArray:  nil,
-				RootStruct: SubStruct{},
+			args: args{entries: []testStruct{
+				{
+					RootString:   "value1",
+					RootDuration: time.Minute,
+				},
+				{
+					RootString:   "value2",
+					RootDuration: time.Hour,
+				},
 			}},
-			want:    nil,
-			wantErr: true,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			f := &Filter{
-				options: tt.fields.options,
-				filter:  tt.fields.filter,
-			}
-			got, err := f.ApplyFilter(tt.args.entries)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("ApplyFilter() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("ApplyFilter() got = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
-func TestFilter_SetOptions(t *testing.T) {
-	type fields struct {
-		options *Options
-		filter  []kov
-	}
-	type args struct {
-		o *Options
-	}
-	tests := []struct {
-		name       string
-		fields     fields
-		args       args
-		wantOption *Options
-	}{
-		{
-			name:       "Correct Option",
-			fields:     fields{},
-			args:       args{o: defaultOption},
-			wantOption: defaultOption,
-		},
-		{
-			name:       "nil option",
-			fields:     fields{},
-			args:       args{o: nil},
-			wantOption: defaultOption,
+			want: []testStruct{
+				{
+					RootString:   "value2",
+					RootDuration: time.Hour,
+				},
+			},
+			wantErr: false,
 		},
 		{
-			name:   "negative max depth",
-			fields: fields{},
-			args: args{o: &Options{
-				MaxDepth:                     -1,
-				EqualKeyValueSeparator:       "",
-				NotEqualKeyValueSeparator:    "",
-				LowerThanKeyValueSeparator:   "",
-				GreaterThanKeyValueSeparator: "",
-				ValueSeparator:               "",
-				KeysSeparator:                "",
-				ComposedKeySeparator:         "",
+			name: "Minimal filter equals interval",
+			fields: fields{
+				options: defaultOption,
+				filter: []kov{
+					{
+						Key:      "RootInt",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"[18..65)"},
+					},
+				},
+			},
+			args: args{entries: []testStruct{
+				{
+					RootString: "value1",
+					RootInt:    17,
+				},
+				{
+					RootString: "value2",
+					RootInt:    18,
+				},
+				{
+					RootString: "value3",
+					RootInt:    65,
+				},
 			}},
-			wantOption: defaultOption,
+			want: []testStruct{
+				{
+					RootString: "value2",
+					RootInt:    18,
+				},
+			},
+			wantErr: false,
 		},
 		{
-			name:   "empty EqualKeyValueSeparator",
-			fields: fields{},
-			args: args{o: &Options{
-				MaxDepth:                     0,
-				EqualKeyValueSeparator:       "",
-				NotEqualKeyValueSeparator:    "!=",
-				LowerThanKeyValueSeparator:   "<",
-				GreaterThanKeyValueSeparator: ">",
-				ValueSeparator:               ",",
-				KeysSeparator:                ":",
-				ComposedKeySeparator:         ".",
+			name: "Minimal filter not equals interval on string",
+			fields: fields{
+				options: defaultOption,
+				filter: []kov{
+					{
+						Key:      "RootString",
+						Operator: defaultOption.NotEqualKeyValueSeparator,
+						Values:   []string{"[a..m)"},
+					},
+				},
+			},
+			args: args{entries: []testStruct{
+				{
+					RootString: "alice",
+				},
+				{
+					RootString: "zoe",
+				},
 			}},
-			wantOption: defaultOption,
+			want: []testStruct{
+				{
+					RootString: "zoe",
+				},
+			},
+			wantErr: false,
 		},
 		{
-			name:   "empty NotEqualKeyValueSeparator",
-			fields: fields{},
-			args: args{o: &Options{
-				MaxDepth:                     0,
-				EqualKeyValueSeparator:       "=",
-				NotEqualKeyValueSeparator:    "",
-				LowerThanKeyValueSeparator:   "<",
-				GreaterThanKeyValueSeparator: ">",
-				ValueSeparator:               ",",
-				KeysSeparator:                ":",
-				ComposedKeySeparator:         ".",
+			name: "Predicates exists and missing",
+			fields: fields{
+				options: defaultOption,
+				filter: []kov{
+					{
+						Key:      "RootPtrStruct",
+						Operator: existsPredicate,
+					},
+					{
+						Key:      "RootMapSimple.entry",
+						Operator: missingPredicate,
+					},
+				},
+			},
+			args: args{entries: []testStruct{
+				{
+					RootString: "value1",
+				},
+				{
+					RootString:    "value2",
+					RootPtrStruct: &testStruct{},
+				},
+				{
+					RootString:    "value3",
+					RootPtrStruct: &testStruct{},
+					RootMapSimple: map[string]string{"entry": ""},
+				},
 			}},
-			wantOption: defaultOption,
+			want: []testStruct{
+				{
+					RootString:    "value2",
+					RootPtrStruct: &testStruct{},
+				},
+			},
+			wantErr: false,
 		},
 		{
-			name:   "empty LowerThanKeyValueSeparator",
-			fields: fields{},
-			args: args{o: &Options{
-				MaxDepth:                     0,
-				EqualKeyValueSeparator:       "=",
-				NotEqualKeyValueSeparator:    "!=",
-				LowerThanKeyValueSeparator:   "",
-				GreaterThanKeyValueSeparator: ">",
-				ValueSeparator:               ",",
-				KeysSeparator:                ":",
-				ComposedKeySeparator:         ".",
+			name: "Predicate not empty on array",
+			fields: fields{
+				options: defaultOption,
+				filter: []kov{
+					{
+						Key:      "RootArraySimple",
+						Operator: notEmptyPredicate,
+					},
+				},
+			},
+			args: args{entries: []testStruct{
+				{
+					RootString: "value1",
+				},
+				{
+					RootString:      "value2",
+					RootArraySimple: []string{},
+				},
+				{
+					RootString:      "value3",
+					RootArraySimple: []string{""},
+				},
 			}},
-			wantOption: defaultOption,
+			want: []testStruct{
+				{
+					RootString:      "value3",
+					RootArraySimple: []string{""},
+				},
+			},
+			wantErr: false,
 		},
 		{
-			name:   "empty GreaterThanKeyValueSeparator",
-			fields: fields{},
-			args: args{o: &Options{
-				MaxDepth:                     0,
-				EqualKeyValueSeparator:       "=",
-				NotEqualKeyValueSeparator:    "!=",
-				LowerThanKeyValueSeparator:   "<",
-				GreaterThanKeyValueSeparator: "",
-				ValueSeparator:               ",",
-				KeysSeparator:                ":",
-				ComposedKeySeparator:         ".",
+			name: "Function len on array",
+			fields: fields{
+				options: defaultOption,
+				filter: []kov{
+					{
+						Key:      "len(RootArraySimple)",
+						Operator: defaultOption.GreaterThanKeyValueSeparator,
+						Values:   []string{"1"},
+					},
+				},
+			},
+			args: args{entries: []testStruct{
+				{
+					RootString:      "value1",
+					RootArraySimple: []string{"val1"},
+				},
+				{
+					RootString:      "value2",
+					RootArraySimple: []string{"val1", "val2"},
+				},
 			}},
-			wantOption: defaultOption,
+			want: []testStruct{
+				{
+					RootString:      "value2",
+					RootArraySimple: []string{"val1", "val2"},
+				},
+			},
+			wantErr: false,
 		},
 		{
-			name:   "empty ValueSeparator",
-			fields: fields{},
-			args: args{o: &Options{
-				MaxDepth:                     0,
-				EqualKeyValueSeparator:       "=",
-				NotEqualKeyValueSeparator:    "!=",
-				LowerThanKeyValueSeparator:   "<",
-				GreaterThanKeyValueSeparator: ">",
-				ValueSeparator:               "",
-				KeysSeparator:                ":",
-				ComposedKeySeparator:         ".",
+			name: "Quantifier all on array",
+			fields: fields{
+				options: defaultOption,
+				filter: []kov{
+					{
+						Key:      "all(RootArraySimple)",
+						Operator: defaultOption.GreaterThanKeyValueSeparator,
+						Values:   []string{"val1"},
+					},
+				},
+			},
+			args: args{entries: []testStruct{
+				{
+					RootString:      "value1",
+					RootArraySimple: []string{"val1", "val2"},
+				},
+				{
+					RootString:      "value2",
+					RootArraySimple: []string{"val2", "val3"},
+				},
+				{
+					RootString: "value3",
+				},
 			}},
-			wantOption: defaultOption,
+			want: []testStruct{
+				{
+					RootString:      "value2",
+					RootArraySimple: []string{"val2", "val3"},
+				},
+				{
+					RootString: "value3",
+				},
+			},
+			wantErr: false,
 		},
 		{
-			name:   "empty KeysSeparator",
-			fields: fields{},
-			args: args{o: &Options{
-				MaxDepth:                     0,
-				EqualKeyValueSeparator:       "=",
-				NotEqualKeyValueSeparator:    "!=",
-				LowerThanKeyValueSeparator:   "<",
-				GreaterThanKeyValueSeparator: ">",
-				ValueSeparator:               ",",
-				KeysSeparator:                "",
-				ComposedKeySeparator:         ".",
+			name: "Quantifier none on array",
+			fields: fields{
+				options: defaultOption,
+				filter: []kov{
+					{
+						Key:      "none(RootArraySimple)",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"spam"},
+					},
+				},
+			},
+			args: args{entries: []testStruct{
+				{
+					RootString:      "value1",
+					RootArraySimple: []string{"val1", "spam"},
+				},
+				{
+					RootString:      "value2",
+					RootArraySimple: []string{"val1", "val2"},
+				},
 			}},
-			wantOption: defaultOption,
+			want: []testStruct{
+				{
+					RootString:      "value2",
+					RootArraySimple: []string{"val1", "val2"},
+				},
+			},
+			wantErr: false,
 		},
 		{
-			name:   "empty ComposedKeySeparator",
-			fields: fields{},
-			args: args{o: &Options{
-				MaxDepth:                     0,
-				EqualKeyValueSeparator:       "=",
-				NotEqualKeyValueSeparator:    "!=",
-				LowerThanKeyValueSeparator:   "<",
-				GreaterThanKeyValueSeparator: ">",
-				ValueSeparator:               ",",
-				KeysSeparator:                ":",
-				ComposedKeySeparator:         "",
+			name: "Quantifier any on not equal",
+			fields: fields{
+				options: defaultOption,
+				filter: []kov{
+					{
+						Key:      "any(RootArraySimple)",
+						Operator: defaultOption.NotEqualKeyValueSeparator,
+						Values:   []string{"val1"},
+					},
+				},
+			},
+			args: args{entries: []testStruct{
+				{
+					RootString:      "value1",
+					RootArraySimple: []string{"val1"},
+				},
+				{
+					RootString:      "value2",
+					RootArraySimple: []string{"val1", "val2"},
+				},
 			}},
-			wantOption: defaultOption,
+			want: []testStruct{
+				{
+					RootString:      "value2",
+					RootArraySimple: []string{"val1", "val2"},
+				},
+			},
+			wantErr: false,
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			f := &Filter{
-				options: tt.fields.options,
-				filter:  tt.fields.filter,
-			}
-			f.SetOptions(tt.args.o)
-			if !reflect.DeepEqual(f.options, tt.wantOption) {
-				t.Errorf("SetOptions() got = %v, wanted %v", f.options, tt.wantOption)
-			}
-		})
-	}
-}
-
-func TestFilter_Init(t *testing.T) {
-	type fields struct {
-		options *Options
-		filter  []kov
-	}
-	type args struct {
-		filterValue string
-		i           interface{}
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		wantErr bool
-	}{
 		{
-			name: "minimal test",
+			name: "Element match on the same array element",
 			fields: fields{
 				options: defaultOption,
-				filter:  nil,
+				filter: []kov{
+					{
+						Key:      "RootArrayPtr",
+						Operator: elemMatchOperator,
+						Sub: []kov{
+							{
+								Key:      "RootString",
+								Operator: defaultOption.EqualKeyValueSeparator,
+								Values:   []string{"val1"},
+							},
+							{
+								Key:      "RootInt",
+								Operator: defaultOption.GreaterThanKeyValueSeparator,
+								Values:   []string{"1"},
+							},
+						},
+					},
+				},
 			},
-			args: args{
-				filterValue: "RootString=val2",
-				i:           testStruct{},
+			args: args{entries: []testStruct{
+				{
+					RootString: "value1",
+					RootArrayPtr: []*testStruct{
+						{RootString: "val1", RootInt: 1},
+						{RootString: "val2", RootInt: 2},
+					},
+				},
+				{
+					RootString: "value2",
+					RootArrayPtr: []*testStruct{
+						nil,
+						{RootString: "val1", RootInt: 2},
+					},
+				},
+			}},
+			want: []testStruct{
+				{
+					RootString: "value2",
+					RootArrayPtr: []*testStruct{
+						nil,
+						{RootString: "val1", RootInt: 2},
+					},
+				},
 			},
 			wantErr: false,
 		},
 		{
-			name: "minimal test without option set",
+			name: "Array selectors",
+			fields: fields{
+				options: defaultOption,
+				filter: []kov{
+					{
+						Key:      "RootArrayPtr[-1].RootString",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"val1"},
+					},
+					{
+						Key:      "RootArraySimple[0:2]",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"val2"},
+					},
+				},
+			},
+			args: args{entries: []testStruct{
+				{
+					RootString:      "value1",
+					RootArrayPtr:    []*testStruct{{RootString: "val2"}, {RootString: "val1"}},
+					RootArraySimple: []string{"val1", "val2"},
+				},
+				{
+					RootString:      "value2",
+					RootArrayPtr:    []*testStruct{{RootString: "val1"}, {RootString: "val2"}},
+					RootArraySimple: []string{"val1", "val2"},
+				},
+				{
+					RootString:      "value3",
+					RootArrayPtr:    []*testStruct{{RootString: "val1"}},
+					RootArraySimple: []string{"val1", "val3", "val2"},
+				},
+				{
+					RootString: "value4",
+				},
+			}},
+			want: []testStruct{
+				{
+					RootString:      "value1",
+					RootArrayPtr:    []*testStruct{{RootString: "val2"}, {RootString: "val1"}},
+					RootArraySimple: []string{"val1", "val2"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Function keys on map",
+			fields: fields{
+				options: defaultOption,
+				filter: []kov{
+					{
+						Key:      "keys(RootMapSimple)",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"[eu-..eu.)"},
+					},
+				},
+			},
+			args: args{entries: []testStruct{
+				{
+					RootString:    "value1",
+					RootMapSimple: map[string]string{"us-east": "val1", "eu-west": "val2"},
+				},
+				{
+					RootString:    "value2",
+					RootMapSimple: map[string]string{"us-east": "val1", "europe": "val2"},
+				},
+			}},
+			want: []testStruct{
+				{
+					RootString:    "value1",
+					RootMapSimple: map[string]string{"us-east": "val1", "eu-west": "val2"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Int map key",
+			fields: fields{
+				options: defaultOption,
+				filter: []kov{
+					{
+						Key:      "RootMapInt.12",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"val1"},
+					},
+				},
+			},
+			args: args{entries: []testStruct{
+				{
+					RootString: "value1",
+					RootMapInt: map[int]string{12: "val1"},
+				},
+				{
+					RootString: "value2",
+					RootMapInt: map[int]string{1: "val1", 2: "val1"},
+				},
+			}},
+			want: []testStruct{
+				{
+					RootString: "value1",
+					RootMapInt: map[int]string{12: "val1"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Insensitive equality",
+			fields: fields{
+				options: defaultOption,
+				filter: []kov{
+					{
+						Key:         "RootArraySimple",
+						Operator:    defaultOption.EqualKeyValueSeparator,
+						Values:      []string{"OPEN"},
+						Insensitive: true,
+					},
+				},
+			},
+			args: args{entries: []testStruct{
+				{
+					RootString:      "value1",
+					RootArraySimple: []string{"Open"},
+				},
+				{
+					RootString:      "value2",
+					RootArraySimple: []string{"closed"},
+				},
+			}},
+			want: []testStruct{
+				{
+					RootString:      "value1",
+					RootArraySimple: []string{"Open"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Case insensitive option",
+			fields: fields{
+				options: &Options{
+					EqualKeyValueSeparator:       "=",
+					NotEqualKeyValueSeparator:    "!=",
+					GreaterThanKeyValueSeparator: ">",
+					LowerThanKeyValueSeparator:   "<",
+					RangeValueSeparator:          "..",
+					ComposedKeySeparator:         ".",
+					CaseInsensitive:              true,
+				},
+				filter: []kov{
+					{
+						Key:      "RootString",
+						Operator: "!=",
+						Values:   []string{"VALUE1"},
+					},
+				},
+			},
+			args: args{entries: []testStruct{
+				{
+					RootString: "value1",
+				},
+				{
+					RootString: "value2",
+				},
+			}},
+			want: []testStruct{
+				{
+					RootString: "value2",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Starts with on map values and contains on array",
+			fields: fields{
+				options: defaultOption,
+				filter: []kov{
+					{
+						Key:      "RootMapSimple.entry1",
+						Operator: defaultOption.StartsWithKeyValueSeparator,
+						Values:   []string{"eu-", "us-"},
+					},
+					{
+						Key:      "RootArraySimple",
+						Operator: defaultOption.ContainsKeyValueSeparator,
+						Values:   []string{"gold"},
+					},
+				},
+			},
+			args: args{entries: []testStruct{
+				{
+					RootString:      "value1",
+					RootMapSimple:   map[string]string{"entry1": "eu-west"},
+					RootArraySimple: []string{"silver", "golden"},
+				},
+				{
+					RootString:      "value2",
+					RootMapSimple:   map[string]string{"entry1": "asia-east"},
+					RootArraySimple: []string{"golden"},
+				},
+				{
+					RootString:      "value3",
+					RootMapSimple:   map[string]string{"entry1": "us-east"},
+					RootArraySimple: []string{"silver"},
+				},
+			}},
+			want: []testStruct{
+				{
+					RootString:      "value1",
+					RootMapSimple:   map[string]string{"entry1": "eu-west"},
+					RootArraySimple: []string{"silver", "golden"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Field references",
+			fields: fields{
+				options: defaultOption,
+				filter: []kov{
+					{
+						Key:      "RootPtrTime",
+						Operator: defaultOption.GreaterThanKeyValueSeparator,
+						Values:   []string{"$RootTime"},
+					},
+					{
+						Key:      "RootString",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"$RootArraySimple", "value3"},
+					},
+				},
+			},
+			args: args{entries: []testStruct{
+				{
+					RootString:      "value1",
+					RootArraySimple: []string{"value0", "value1"},
+					RootTime:        testNow,
+					RootPtrTime:     &testTomorrow,
+				},
+				{
+					RootString:      "value2",
+					RootArraySimple: []string{"value1"},
+					RootTime:        testNow,
+					RootPtrTime:     &testTomorrow,
+				},
+				{
+					RootString:  "value3",
+					RootTime:    testTomorrow,
+					RootPtrTime: &testTomorrow,
+				},
+				{
+					RootString: "value3",
+					RootTime:   testNow,
+				},
+			}},
+			want: []testStruct{
+				{
+					RootString:      "value1",
+					RootArraySimple: []string{"value0", "value1"},
+					RootTime:        testNow,
+					RootPtrTime:     &testTomorrow,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Arithmetic expression",
+			fields: fields{
+				options: defaultOption,
+				filter: []kov{
+					{
+						Key:      "(RootInt*RootFloat)",
+						Operator: defaultOption.GreaterThanKeyValueSeparator,
+						Values:   []string{"100"},
+					},
+				},
+			},
+			args: args{entries: []testStruct{
+				{
+					RootString: "value1",
+					RootInt:    20,
+					RootFloat:  5.5,
+				},
+				{
+					RootString: "value2",
+					RootInt:    20,
+					RootFloat:  5,
+				},
+			}},
+			want: []testStruct{
+				{
+					RootString: "value1",
+					RootInt:    20,
+					RootFloat:  5.5,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Scalar functions",
+			fields: fields{
+				options: defaultOption,
+				filter: []kov{
+					{
+						Key:      "lower(trim(RootArraySimple))",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"open"},
+					},
+					{
+						Key:      "year(RootTime)",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"2020"},
+					},
+				},
+			},
+			args: args{entries: []testStruct{
+				{
+					RootString:      "value1",
+					RootArraySimple: []string{"closed", " Open "},
+					RootTime:        testNow,
+				},
+				{
+					RootString:      "value2",
+					RootArraySimple: []string{"Open"},
+					RootTime:        testTomorrow.AddDate(1, 0, 0),
+				},
+			}},
+			want: []testStruct{
+				{
+					RootString:      "value1",
+					RootArraySimple: []string{"closed", " Open "},
+					RootTime:        testNow,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Composite filter",
+			fields: fields{
+				options: defaultOption,
+				filter: []kov{
+					{
+						Key:      "RootArray.SubString",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"string1"},
+					},
+				},
+			},
+			args: args{entries: []testStruct{
+				{
+					RootString: "value1",
+					RootInt:    0,
+					RootFloat:  0,
+					RootBool:   false,
+					RootArray: []SubStruct{
+						{SubString: "string1"},
+					},
+					RootStruct: SubStruct{},
+				},
+				{
+					RootString: "value2",
+					RootInt:    0,
+					RootFloat:  0,
+					RootBool:   false,
+					RootArray: []SubStruct{
+						{SubString: "string2"},
+					},
+					RootStruct: SubStruct{},
+				},
+				{
+					RootString: "value3",
+					RootInt:    0,
+					RootFloat:  0,
+					RootBool:   false,
+					RootArray: []SubStruct{
+						{SubString: "string1"},
+						{SubString: "string2"},
+					},
+					RootStruct: SubStruct{},
+				},
+			}},
+			want: []testStruct{
+				{
+					RootString: "value1",
+					RootInt:    0,
+					RootFloat:  0,
+					RootBool:   false,
+					RootArray: []SubStruct{
+						{SubString: "string1"},
+					},
+					RootStruct: SubStruct{},
+				},
+				{
+					RootString: "value3",
+					RootInt:    0,
+					RootFloat:  0,
+					RootBool:   false,
+					RootArray: []SubStruct{
+						{SubString: "string1"},
+						{SubString: "string2"},
+					},
+					RootStruct: SubStruct{},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Minimal filter",
+			fields: fields{
+				options: defaultOption,
+				filter: []kov{
+					{
+						Key:      "RootString",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"value1"},
+					},
+				},
+			},
+			args: args{entries: testStruct{
+				RootString: "value1",
+				RootInt:    0,
+				RootFloat:  0,
+				RootBool:   false,
+				RootArray:  nil,
+				RootStruct: SubStruct{},
+			}},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{
+				options: tt.fields.options,
+				filter:  tt.fields.filter,
+			}
+			got, err := f.ApplyFilter(tt.args.entries)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ApplyFilter() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ApplyFilter() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter_SetOptions(t *testing.T) {
+	type fields struct {
+		options *Options
+		filter  []kov
+	}
+	type args struct {
+		o *Options
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantOption *Options
+	}{
+		{
+			name:       "Correct Option",
+			fields:     fields{},
+			args:       args{o: defaultOption},
+			wantOption: defaultOption,
+		},
+		{
+			name:       "nil option",
+			fields:     fields{},
+			args:       args{o: nil},
+			wantOption: defaultOption,
+		},
+		{
+			name:   "negative max depth",
+			fields: fields{},
+			args: args{o: &Options{
+				MaxDepth:                     -1,
+				EqualKeyValueSeparator:       "",
+				NotEqualKeyValueSeparator:    "",
+				LowerThanKeyValueSeparator:   "",
+				GreaterThanKeyValueSeparator: "",
+				ValueSeparator:               "",
+				KeysSeparator:                "",
+				ComposedKeySeparator:         "",
+				FieldReferencePrefix:         "$",
+				InsensitiveModifier:          "~",
+			}},
+			wantOption: defaultOption,
+		},
+		{
+			name:   "empty EqualKeyValueSeparator",
+			fields: fields{},
+			args: args{o: &Options{
+				MaxDepth:                     0,
+				EqualKeyValueSeparator:       "",
+				NotEqualKeyValueSeparator:    "!=",
+				LowerThanKeyValueSeparator:   "<",
+				GreaterThanKeyValueSeparator: ">",
+				ValueSeparator:               ",",
+				KeysSeparator:                ":",
+				ComposedKeySeparator:         ".",
+				FieldReferencePrefix:         "$",
+				InsensitiveModifier:          "~",
+			}},
+			wantOption: defaultOption,
+		},
+		{
+			name:   "empty NotEqualKeyValueSeparator",
+			fields: fields{},
+			args: args{o: &Options{
+				MaxDepth:                     0,
+				EqualKeyValueSeparator:       "=",
+				NotEqualKeyValueSeparator:    "",
+				LowerThanKeyValueSeparator:   "<",
+				GreaterThanKeyValueSeparator: ">",
+				ValueSeparator:               ",",
+				KeysSeparator:                ":",
+				ComposedKeySeparator:         ".",
+				FieldReferencePrefix:         "$",
+				InsensitiveModifier:          "~",
+			}},
+			wantOption: defaultOption,
+		},
+		{
+			name:   "empty LowerThanKeyValueSeparator",
+			fields: fields{},
+			args: args{o: &Options{
+				MaxDepth:                     0,
+				EqualKeyValueSeparator:       "=",
+				NotEqualKeyValueSeparator:    "!=",
+				LowerThanKeyValueSeparator:   "",
+				GreaterThanKeyValueSeparator: ">",
+				ValueSeparator:               ",",
+				KeysSeparator:                ":",
+				ComposedKeySeparator:         ".",
+				FieldReferencePrefix:         "$",
+				InsensitiveModifier:          "~",
+			}},
+			wantOption: defaultOption,
+		},
+		{
+			name:   "empty GreaterThanKeyValueSeparator",
+			fields: fields{},
+			args: args{o: &Options{
+				MaxDepth:                     0,
+				EqualKeyValueSeparator:       "=",
+				NotEqualKeyValueSeparator:    "!=",
+				LowerThanKeyValueSeparator:   "<",
+				GreaterThanKeyValueSeparator: "",
+				ValueSeparator:               ",",
+				KeysSeparator:                ":",
+				ComposedKeySeparator:         ".",
+				FieldReferencePrefix:         "$",
+				InsensitiveModifier:          "~",
+			}},
+			wantOption: defaultOption,
+		},
+		{
+			name:   "empty ValueSeparator",
+			fields: fields{},
+			args: args{o: &Options{
+				MaxDepth:                     0,
+				EqualKeyValueSeparator:       "=",
+				NotEqualKeyValueSeparator:    "!=",
+				LowerThanKeyValueSeparator:   "<",
+				GreaterThanKeyValueSeparator: ">",
+				ValueSeparator:               "",
+				KeysSeparator:                ":",
+				ComposedKeySeparator:         ".",
+				FieldReferencePrefix:         "$",
+				InsensitiveModifier:          "~",
+			}},
+			wantOption: defaultOption,
+		},
+		{
+			name:   "empty KeysSeparator",
+			fields: fields{},
+			args: args{o: &Options{
+				MaxDepth:                     0,
+				EqualKeyValueSeparator:       "=",
+				NotEqualKeyValueSeparator:    "!=",
+				LowerThanKeyValueSeparator:   "<",
+				GreaterThanKeyValueSeparator: ">",
+				ValueSeparator:               ",",
+				KeysSeparator:                "",
+				ComposedKeySeparator:         ".",
+				FieldReferencePrefix:         "$",
+				InsensitiveModifier:          "~",
+			}},
+			wantOption: defaultOption,
+		},
+		{
+			name:   "empty ComposedKeySeparator",
+			fields: fields{},
+			args: args{o: &Options{
+				MaxDepth:                     0,
+				EqualKeyValueSeparator:       "=",
+				NotEqualKeyValueSeparator:    "!=",
+				LowerThanKeyValueSeparator:   "<",
+				GreaterThanKeyValueSeparator: ">",
+				ValueSeparator:               ",",
+				KeysSeparator:                ":",
+				ComposedKeySeparator:         "",
+				FieldReferencePrefix:         "$",
+				InsensitiveModifier:          "~",
+			}},
+			wantOption: defaultOption,
+		},
+		{
+			name:   "empty RangeValueSeparator",
+			fields: fields{},
+			args: args{o: &Options{
+				MaxDepth:                     0,
+				EqualKeyValueSeparator:       "=",
+				NotEqualKeyValueSeparator:    "!=",
+				LowerThanKeyValueSeparator:   "<",
+				GreaterThanKeyValueSeparator: ">",
+				ValueSeparator:               ",",
+				KeysSeparator:                ":",
+				ComposedKeySeparator:         ".",
+				RangeValueSeparator:          "",
+				FieldReferencePrefix:         "$",
+				InsensitiveModifier:          "~",
+			}},
+			wantOption: defaultOption,
+		},
+		{
+			name:   "empty InsensitiveModifier",
+			fields: fields{},
+			args: args{o: &Options{
+				MaxDepth:                     0,
+				EqualKeyValueSeparator:       "=",
+				NotEqualKeyValueSeparator:    "!=",
+				LowerThanKeyValueSeparator:   "<",
+				GreaterThanKeyValueSeparator: ">",
+				ValueSeparator:               ",",
+				KeysSeparator:                ":",
+				ComposedKeySeparator:         ".",
+				RangeValueSeparator:          "..",
+				InsensitiveModifier:          "",
+				FieldReferencePrefix:         "$",
+			}},
+			wantOption: func() *Options {
+				o := *defaultOption
+				o.InsensitiveModifier = ""
+				return &o
+			}(),
+		},
+		{
+			name:   "empty FieldReferencePrefix",
+			fields: fields{},
+			args: args{o: &Options{
+				EqualKeyValueSeparator:       "=",
+				NotEqualKeyValueSeparator:    "!=",
+				LowerThanKeyValueSeparator:   "<",
+				GreaterThanKeyValueSeparator: ">",
+				ValueSeparator:               ",",
+				KeysSeparator:                ":",
+				ComposedKeySeparator:         ".",
+				RangeValueSeparator:          "..",
+				InsensitiveModifier:          "~",
+				FieldReferencePrefix:         "",
+			}},
+			wantOption: func() *Options {
+				o := *defaultOption
+				o.FieldReferencePrefix = ""
+				return &o
+			}(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{
+				options: tt.fields.options,
+				filter:  tt.fields.filter,
+			}
+			f.SetOptions(tt.args.o)
+			if !reflect.DeepEqual(f.options, tt.wantOption) {
+				t.Errorf("SetOptions() got = %v, wanted %v", f.options, tt.wantOption)
+			}
+		})
+	}
+}
+
+func TestFilter_Init(t *testing.T) {
+	type fields struct {
+		options *Options
+		filter  []kov
+	}
+	type args struct {
+		filterValue string
+		i           interface{}
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "minimal test",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil,
+			},
+			args: args{
+				filterValue: "RootString=val2",
+				i:           testStruct{},
+			},
+			wantErr: false,
+		},
+		{
+			name: "minimal test without option set",
+			fields: fields{
+				options: nil,
+				filter:  nil,
+			},
+			args: args{
+				filterValue: "RootString=val2",
+				i:           testStruct{},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error double key",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil,
+			},
+			args: args{
+				filterValue: "RootString=val2:RootString=val1",
+				i:           testStruct{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "error unknown key",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil,
+			},
+			args: args{
+				filterValue: "unknownKey=val1",
+				i:           testStruct{},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{
+				options: tt.fields.options,
+				filter:  tt.fields.filter,
+			}
+			if err := f.Init(tt.args.filterValue, tt.args.i); (err != nil) != tt.wantErr {
+				t.Errorf("Init() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFilter_compileFilter(t *testing.T) {
+	type fields struct {
+		options *Options
+		filter  []kov
+	}
+	type args struct {
+		filterMap []kov
+		t         reflect.Type
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantFilter []kov
+		wantErr    bool
+	}{
+		{
+			name: "minimal test on Name",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil,
+			},
+			args: args{
+				filterMap: []kov{
+					{
+						Key:      "stringRoot",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"val1"},
+					},
+				},
+				t: reflect.TypeOf(testStruct{}),
+			},
+			wantFilter: []kov{
+				{
+					Key:      "RootString",
+					Operator: defaultOption.EqualKeyValueSeparator,
+					Values:   []string{"val1"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "minimal test on Tag",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil,
+			},
+			args: args{
+				filterMap: []kov{
+					{
+						Key:      "stringRoot",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"val1"},
+					},
+				},
+				t: reflect.TypeOf(testStruct{}),
+			},
+			wantFilter: []kov{
+				{
+					Key:      "RootString",
+					Operator: defaultOption.EqualKeyValueSeparator,
+					Values:   []string{"val1"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Nokey",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil,
+			},
+			args: args{
+				filterMap: []kov{
+					{
+						Key:      "noKey",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"val1"},
+					},
+				},
+				t: reflect.TypeOf(testStruct{}),
+			},
+			wantFilter: []kov{},
+			wantErr:    true,
+		},
+		{
+			name: "filter in struct on Name",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil,
+			},
+			args: args{
+				filterMap: []kov{
+					{
+						Key:      "RootStruct.SubString",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"val1"},
+					},
+				},
+				t: reflect.TypeOf(testStruct{}),
+			},
+			wantFilter: []kov{
+				{
+					Key:      "RootStruct.SubString",
+					Operator: defaultOption.EqualKeyValueSeparator,
+					Values:   []string{"val1"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "filter in struct on Tag",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil,
+			},
+			args: args{
+				filterMap: []kov{
+					{
+						Key:      "structRoot.stringSub",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"val1"},
+					},
+				},
+				t: reflect.TypeOf(testStruct{}),
+			},
+			wantFilter: []kov{
+				{
+					Key:      "RootStruct.SubString",
+					Operator: defaultOption.EqualKeyValueSeparator,
+					Values:   []string{"val1"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "filter in struct on mixed tag/name",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil,
+			},
+			args: args{
+				filterMap: []kov{
+					{
+						Key:      "RootStruct.stringSub",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"val1"},
+					},
+				},
+				t: reflect.TypeOf(testStruct{}),
+			},
+			wantFilter: []kov{
+				{
+					Key:      "RootStruct.SubString",
+					Operator: defaultOption.EqualKeyValueSeparator,
+					Values:   []string{"val1"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "filter on Map with mixed tag/name",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil,
+			},
+			args: args{
+				filterMap: []kov{
+					{
+						Key:      "RootMap.entry1.stringSub",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"val1"},
+					},
+				},
+				t: reflect.TypeOf(testStruct{}),
+			},
+			wantFilter: []kov{
+				{
+					Key:      "RootMap.entry1.SubString",
+					Operator: defaultOption.EqualKeyValueSeparator,
+					Values:   []string{"val1"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Filter in array of ptr",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil,
+			},
+			args: args{
+				filterMap: []kov{
+					{
+						Key:      "RootArrayPtr.RootString",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"val1"},
+					},
+				},
+				t: reflect.TypeOf(testStruct{}),
+			},
+			wantFilter: []kov{
+				{
+					Key:      "RootArrayPtr.RootString",
+					Operator: defaultOption.EqualKeyValueSeparator,
+					Values:   []string{"val1"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Filter in map of ptr",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil,
+			},
+			args: args{
+				filterMap: []kov{
+					{
+						Key:      "RootMapPtr.RootString",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"val1"},
+					},
+				},
+				t: reflect.TypeOf(testStruct{}),
+			},
+			wantFilter: []kov{
+				{
+					Key:      "RootMapPtr.RootString",
+					Operator: defaultOption.EqualKeyValueSeparator,
+					Values:   []string{"val1"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Filter in array values on Name",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil,
+			},
+			args: args{
+				filterMap: []kov{
+					{
+						Key:      "RootArray",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"val1"},
+					},
+				},
+				t: reflect.TypeOf(testStruct{}),
+			},
+			wantFilter: []kov{
+				{
+					Key:      "RootArray",
+					Operator: defaultOption.EqualKeyValueSeparator,
+					Values:   []string{"val1"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Filter in array values on Name, deeper",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil,
+			},
+			args: args{
+				filterMap: []kov{
+					{
+						Key:      "RootArray.SubString",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"val1"},
+					},
+				},
+				t: reflect.TypeOf(testStruct{}),
+			},
+			wantFilter: []kov{
+				{
+					Key:      "RootArray.SubString",
+					Operator: defaultOption.EqualKeyValueSeparator,
+					Values:   []string{"val1"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Filter on Matrix",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil,
+			},
+			args: args{
+				filterMap: []kov{
+					{
+						Key:      "matrix",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"val1"},
+					},
+				},
+				t: reflect.TypeOf(testStruct{}),
+			},
+			wantFilter: []kov{
+				{
+					Key:      "Matrix",
+					Operator: defaultOption.EqualKeyValueSeparator,
+					Values:   []string{"val1"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Filter in array values on Tag",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil,
+			},
+			args: args{
+				filterMap: []kov{
+					{
+						Key:      "arrayRoot",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"val1"},
+					},
+				},
+				t: reflect.TypeOf(testStruct{}),
+			},
+			wantFilter: []kov{
+				{
+					Key:      "RootArray",
+					Operator: defaultOption.EqualKeyValueSeparator,
+					Values:   []string{"val1"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Greater than on string",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil,
+			},
+			args: args{
+				filterMap: []kov{
+					{
+						Key:      "arrayRoot.stringSub",
+						Operator: defaultOption.GreaterThanKeyValueSeparator,
+						Values:   []string{"M"},
+					},
+				},
+				t: reflect.TypeOf(testStruct{}),
+			},
+			wantFilter: []kov{
+				{
+					Key:      "RootArray.SubString",
+					Operator: defaultOption.GreaterThanKeyValueSeparator,
+					Values:   []string{"M"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Lower than with no numeric value on numeric",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil,
+			},
+			args: args{
+				filterMap: []kov{
+					{
+						Key:      "intRoot",
+						Operator: defaultOption.LowerThanKeyValueSeparator,
+						Values:   []string{"M"},
+					},
+				},
+				t: reflect.TypeOf(testStruct{}),
+			},
+			wantFilter: []kov{},
+			wantErr:    true,
+		},
+		{
+			name: "Time with relative and date only values",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil,
+			},
+			args: args{
+				filterMap: []kov{
+					{
+						Key:      "ptrTimeRoot",
+						Operator: defaultOption.GreaterThanKeyValueSeparator,
+						Values:   []string{"now-7d"},
+					},
+					{
+						Key:      "timeRoot",
+						Operator: defaultOption.NotEqualKeyValueSeparator,
+						Values:   []string{"2020-01-31", "2020-02-01T10:00:00Z"},
+					},
+				},
+				t: reflect.TypeOf(testStruct{}),
+			},
+			wantFilter: []kov{
+				{
+					Key:      "RootPtrTime",
+					Operator: defaultOption.GreaterThanKeyValueSeparator,
+					Values:   []string{"now-7d"},
+				},
+				{
+					Key:      "RootTime",
+					Operator: defaultOption.NotEqualKeyValueSeparator,
+					Values:   []string{"2020-01-31", "2020-02-01T10:00:00Z"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Time with invalid value",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil,
+			},
+			args: args{
+				filterMap: []kov{
+					{
+						Key:      "timeRoot",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"yesterday"},
+					},
+				},
+				t: reflect.TypeOf(testStruct{}),
+			},
+			wantFilter: []kov{},
+			wantErr:    true,
+		},
+		{
+			name: "Time browsed deeper",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil,
+			},
+			args: args{
+				filterMap: []kov{
+					{
+						Key:      "timeRoot.wall",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"1"},
+					},
+				},
+				t: reflect.TypeOf(testStruct{}),
+			},
+			wantFilter: []kov{},
+			wantErr:    true,
+		},
+		{
+			name: "Duration with invalid value",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil,
+			},
+			args: args{
+				filterMap: []kov{
+					{
+						Key:      "durationRoot",
+						Operator: defaultOption.LowerThanKeyValueSeparator,
+						Values:   []string{"1 hour"},
+					},
+				},
+				t: reflect.TypeOf(testStruct{}),
+			},
+			wantFilter: []kov{},
+			wantErr:    true,
+		},
+		{
+			name: "Equal with no numeric value on numeric",
 			fields: fields{
-				options: nil,
+				options: defaultOption,
 				filter:  nil,
 			},
 			args: args{
-				filterValue: "RootString=val2",
-				i:           testStruct{},
+				filterMap: []kov{
+					{
+						Key:      "intRoot",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"1", "one"},
+					},
+				},
+				t: reflect.TypeOf(testStruct{}),
 			},
-			wantErr: false,
+			wantFilter: []kov{},
+			wantErr:    true,
 		},
 		{
-			name: "error double key",
+			name: "Interval on numeric and time",
 			fields: fields{
 				options: defaultOption,
 				filter:  nil,
 			},
 			args: args{
-				filterValue: "RootString=val2:RootString=val1",
-				i:           testStruct{},
+				filterMap: []kov{
+					{
+						Key:      "intRoot",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"[18..65)", "(..0]"},
+					},
+					{
+						Key:      "timeRoot",
+						Operator: defaultOption.NotEqualKeyValueSeparator,
+						Values:   []string{"[2020-01-01..now-7d]"},
+					},
+				},
+				t: reflect.TypeOf(testStruct{}),
 			},
-			wantErr: true,
+			wantFilter: []kov{
+				{
+					Key:      "RootInt",
+					Operator: defaultOption.EqualKeyValueSeparator,
+					Values:   []string{"[18..65)", "(..0]"},
+				},
+				{
+					Key:      "RootTime",
+					Operator: defaultOption.NotEqualKeyValueSeparator,
+					Values:   []string{"[2020-01-01..now-7d]"},
+				},
+			},
+			wantErr: false,
 		},
 		{
-			name: "error unknown key",
+			name: "Interval with no numeric bound on numeric",
 			fields: fields{
 				options: defaultOption,
 				filter:  nil,
 			},
 			args: args{
-				filterValue: "unknownKey=val1",
-				i:           testStruct{},
+				filterMap: []kov{
+					{
+						Key:      "intRoot",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"[a..z]"},
+					},
+				},
+				t: reflect.TypeOf(testStruct{}),
 			},
-			wantErr: true,
+			wantFilter: []kov{},
+			wantErr:    true,
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			f := &Filter{
-				options: tt.fields.options,
-				filter:  tt.fields.filter,
-			}
-			if err := f.Init(tt.args.filterValue, tt.args.i); (err != nil) != tt.wantErr {
-				t.Errorf("Init() error = %v, wantErr %v", err, tt.wantErr)
-			}
-		})
-	}
-}
-
-func TestFilter_compileFilter(t *testing.T) {
-	type fields struct {
-		options *Options
-		filter  []kov
-	}
-	type args struct {
-		filterMap []kov
-		t         reflect.Type
-	}
-	tests := []struct {
-		name       string
-		fields     fields
-		args       args
-		wantFilter []kov
-		wantErr    bool
-	}{
 		{
-			name: "minimal test on Name",
+			name: "Interval on bool",
 			fields: fields{
 				options: defaultOption,
 				filter:  nil,
@@ -594,24 +1995,18 @@ func TestFilter_compileFilter(t *testing.T) {
 			args: args{
 				filterMap: []kov{
 					{
-						Key:      "stringRoot",
+						Key:      "boolRoot",
 						Operator: defaultOption.EqualKeyValueSeparator,
-						Values:   []string{"val1"},
+						Values:   []string{"[false..true]"},
 					},
 				},
 				t: reflect.TypeOf(testStruct{}),
 			},
-			wantFilter: []kov{
-				{
-					Key:      "RootString",
-					Operator: defaultOption.EqualKeyValueSeparator,
-					Values:   []string{"val1"},
-				},
-			},
-			wantErr: false,
+			wantFilter: []kov{},
+			wantErr:    true,
 		},
 		{
-			name: "minimal test on Tag",
+			name: "Empty predicates on string and array",
 			fields: fields{
 				options: defaultOption,
 				filter:  nil,
@@ -620,8 +2015,11 @@ func TestFilter_compileFilter(t *testing.T) {
 				filterMap: []kov{
 					{
 						Key:      "stringRoot",
-						Operator: defaultOption.EqualKeyValueSeparator,
-						Values:   []string{"val1"},
+						Operator: emptyPredicate,
+					},
+					{
+						Key:      "arrayRoot",
+						Operator: notEmptyPredicate,
 					},
 				},
 				t: reflect.TypeOf(testStruct{}),
@@ -629,14 +2027,17 @@ func TestFilter_compileFilter(t *testing.T) {
 			wantFilter: []kov{
 				{
 					Key:      "RootString",
-					Operator: defaultOption.EqualKeyValueSeparator,
-					Values:   []string{"val1"},
+					Operator: emptyPredicate,
+				},
+				{
+					Key:      "RootArray",
+					Operator: notEmptyPredicate,
 				},
 			},
 			wantErr: false,
 		},
 		{
-			name: "Nokey",
+			name: "Empty predicate on numeric",
 			fields: fields{
 				options: defaultOption,
 				filter:  nil,
@@ -644,9 +2045,8 @@ func TestFilter_compileFilter(t *testing.T) {
 			args: args{
 				filterMap: []kov{
 					{
-						Key:      "noKey",
-						Operator: defaultOption.EqualKeyValueSeparator,
-						Values:   []string{"val1"},
+						Key:      "intRoot",
+						Operator: emptyPredicate,
 					},
 				},
 				t: reflect.TypeOf(testStruct{}),
@@ -655,7 +2055,7 @@ func TestFilter_compileFilter(t *testing.T) {
 			wantErr:    true,
 		},
 		{
-			name: "filter in struct on Name",
+			name: "Element match on array of struct pointers",
 			fields: fields{
 				options: defaultOption,
 				filter:  nil,
@@ -663,24 +2063,58 @@ func TestFilter_compileFilter(t *testing.T) {
 			args: args{
 				filterMap: []kov{
 					{
-						Key:      "RootStruct.SubString",
-						Operator: defaultOption.EqualKeyValueSeparator,
-						Values:   []string{"val1"},
+						Key:      "arrayRootPtr",
+						Operator: elemMatchOperator,
+						Sub: []kov{
+							{
+								Key:      "stringRoot",
+								Operator: defaultOption.EqualKeyValueSeparator,
+								Values:   []string{"val1"},
+							},
+							{
+								Key:      "arrayRoot",
+								Operator: elemMatchOperator,
+								Sub: []kov{
+									{
+										Key:      "stringSub",
+										Operator: defaultOption.EqualKeyValueSeparator,
+										Values:   []string{"val2"},
+									},
+								},
+							},
+						},
 					},
 				},
 				t: reflect.TypeOf(testStruct{}),
 			},
 			wantFilter: []kov{
 				{
-					Key:      "RootStruct.SubString",
-					Operator: defaultOption.EqualKeyValueSeparator,
-					Values:   []string{"val1"},
+					Key:      "RootArrayPtr",
+					Operator: elemMatchOperator,
+					Sub: []kov{
+						{
+							Key:      "RootString",
+							Operator: defaultOption.EqualKeyValueSeparator,
+							Values:   []string{"val1"},
+						},
+						{
+							Key:      "RootArray",
+							Operator: elemMatchOperator,
+							Sub: []kov{
+								{
+									Key:      "SubString",
+									Operator: defaultOption.EqualKeyValueSeparator,
+									Values:   []string{"val2"},
+								},
+							},
+						},
+					},
 				},
 			},
 			wantErr: false,
 		},
 		{
-			name: "filter in struct on Tag",
+			name: "Element match with unknown sub key",
 			fields: fields{
 				options: defaultOption,
 				filter:  nil,
@@ -688,24 +2122,24 @@ func TestFilter_compileFilter(t *testing.T) {
 			args: args{
 				filterMap: []kov{
 					{
-						Key:      "structRoot.stringSub",
-						Operator: defaultOption.EqualKeyValueSeparator,
-						Values:   []string{"val1"},
+						Key:      "arrayRoot",
+						Operator: elemMatchOperator,
+						Sub: []kov{
+							{
+								Key:      "stringRoot",
+								Operator: defaultOption.EqualKeyValueSeparator,
+								Values:   []string{"val1"},
+							},
+						},
 					},
 				},
 				t: reflect.TypeOf(testStruct{}),
 			},
-			wantFilter: []kov{
-				{
-					Key:      "RootStruct.SubString",
-					Operator: defaultOption.EqualKeyValueSeparator,
-					Values:   []string{"val1"},
-				},
-			},
-			wantErr: false,
+			wantFilter: []kov{},
+			wantErr:    true,
 		},
 		{
-			name: "filter in struct on mixed tag/name",
+			name: "Element match on array of string",
 			fields: fields{
 				options: defaultOption,
 				filter:  nil,
@@ -713,24 +2147,24 @@ func TestFilter_compileFilter(t *testing.T) {
 			args: args{
 				filterMap: []kov{
 					{
-						Key:      "RootStruct.stringSub",
-						Operator: defaultOption.EqualKeyValueSeparator,
-						Values:   []string{"val1"},
+						Key:      "arrayRootSimple",
+						Operator: elemMatchOperator,
+						Sub: []kov{
+							{
+								Key:      "stringRoot",
+								Operator: defaultOption.EqualKeyValueSeparator,
+								Values:   []string{"val1"},
+							},
+						},
 					},
 				},
 				t: reflect.TypeOf(testStruct{}),
 			},
-			wantFilter: []kov{
-				{
-					Key:      "RootStruct.SubString",
-					Operator: defaultOption.EqualKeyValueSeparator,
-					Values:   []string{"val1"},
-				},
-			},
-			wantErr: false,
+			wantFilter: []kov{},
+			wantErr:    true,
 		},
 		{
-			name: "filter on Map with mixed tag/name",
+			name: "Array selectors",
 			fields: fields{
 				options: defaultOption,
 				filter:  nil,
@@ -738,24 +2172,34 @@ func TestFilter_compileFilter(t *testing.T) {
 			args: args{
 				filterMap: []kov{
 					{
-						Key:      "RootMap.entry1.stringSub",
+						Key:      "arrayRoot[-1].stringSub",
 						Operator: defaultOption.EqualKeyValueSeparator,
 						Values:   []string{"val1"},
 					},
+					{
+						Key:      "mapRootArrayOfString.entry1[0:3]",
+						Operator: defaultOption.EqualKeyValueSeparator,
+						Values:   []string{"val2"},
+					},
 				},
 				t: reflect.TypeOf(testStruct{}),
 			},
 			wantFilter: []kov{
 				{
-					Key:      "RootMap.entry1.SubString",
+					Key:      "RootArray[-1].SubString",
 					Operator: defaultOption.EqualKeyValueSeparator,
 					Values:   []string{"val1"},
 				},
+				{
+					Key:      "RootMapArrayOfSimple.entry1[0:3]",
+					Operator: defaultOption.EqualKeyValueSeparator,
+					Values:   []string{"val2"},
+				},
 			},
 			wantErr: false,
 		},
 		{
-			name: "Filter in array of ptr",
+			name: "Array selector on struct",
 			fields: fields{
 				options: defaultOption,
 				filter:  nil,
@@ -763,24 +2207,18 @@ func TestFilter_compileFilter(t *testing.T) {
 			args: args{
 				filterMap: []kov{
 					{
-						Key:      "RootArrayPtr.RootString",
+						Key:      "structRoot[0].stringSub",
 						Operator: defaultOption.EqualKeyValueSeparator,
 						Values:   []string{"val1"},
 					},
 				},
 				t: reflect.TypeOf(testStruct{}),
 			},
-			wantFilter: []kov{
-				{
-					Key:      "RootArrayPtr.RootString",
-					Operator: defaultOption.EqualKeyValueSeparator,
-					Values:   []string{"val1"},
-				},
-			},
-			wantErr: false,
+			wantFilter: []kov{},
+			wantErr:    true,
 		},
 		{
-			name: "Filter in map of ptr",
+			name: "Invalid array selector",
 			fields: fields{
 				options: defaultOption,
 				filter:  nil,
@@ -788,24 +2226,18 @@ func TestFilter_compileFilter(t *testing.T) {
 			args: args{
 				filterMap: []kov{
 					{
-						Key:      "RootMapPtr.RootString",
+						Key:      "arrayRoot[first].stringSub",
 						Operator: defaultOption.EqualKeyValueSeparator,
 						Values:   []string{"val1"},
 					},
 				},
 				t: reflect.TypeOf(testStruct{}),
 			},
-			wantFilter: []kov{
-				{
-					Key:      "RootMapPtr.RootString",
-					Operator: defaultOption.EqualKeyValueSeparator,
-					Values:   []string{"val1"},
-				},
-			},
-			wantErr: false,
+			wantFilter: []kov{},
+			wantErr:    true,
 		},
 		{
-			name: "Filter in array values on Name",
+			name: "Int map key",
 			fields: fields{
 				options: defaultOption,
 				filter:  nil,
@@ -813,7 +2245,7 @@ func TestFilter_compileFilter(t *testing.T) {
 			args: args{
 				filterMap: []kov{
 					{
-						Key:      "RootArray",
+						Key:      "mapRootInt.12",
 						Operator: defaultOption.EqualKeyValueSeparator,
 						Values:   []string{"val1"},
 					},
@@ -822,7 +2254,7 @@ func TestFilter_compileFilter(t *testing.T) {
 			},
 			wantFilter: []kov{
 				{
-					Key:      "RootArray",
+					Key:      "RootMapInt.12",
 					Operator: defaultOption.EqualKeyValueSeparator,
 					Values:   []string{"val1"},
 				},
@@ -830,7 +2262,7 @@ func TestFilter_compileFilter(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name: "Filter in array values on Name, deeper",
+			name: "Not int map key",
 			fields: fields{
 				options: defaultOption,
 				filter:  nil,
@@ -838,24 +2270,37 @@ func TestFilter_compileFilter(t *testing.T) {
 			args: args{
 				filterMap: []kov{
 					{
-						Key:      "RootArray.SubString",
+						Key:      "mapRootInt.entry1",
 						Operator: defaultOption.EqualKeyValueSeparator,
 						Values:   []string{"val1"},
 					},
 				},
 				t: reflect.TypeOf(testStruct{}),
 			},
-			wantFilter: []kov{
-				{
-					Key:      "RootArray.SubString",
-					Operator: defaultOption.EqualKeyValueSeparator,
-					Values:   []string{"val1"},
+			wantFilter: []kov{},
+			wantErr:    true,
+		},
+		{
+			name: "Contains on numeric",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil,
+			},
+			args: args{
+				filterMap: []kov{
+					{
+						Key:      "intRoot",
+						Operator: defaultOption.ContainsKeyValueSeparator,
+						Values:   []string{"1"},
+					},
 				},
+				t: reflect.TypeOf(testStruct{}),
 			},
-			wantErr: false,
+			wantFilter: []kov{},
+			wantErr:    true,
 		},
 		{
-			name: "Filter on Matrix",
+			name: "Arithmetic expression",
 			fields: fields{
 				options: defaultOption,
 				filter:  nil,
@@ -863,24 +2308,24 @@ func TestFilter_compileFilter(t *testing.T) {
 			args: args{
 				filterMap: []kov{
 					{
-						Key:      "matrix",
-						Operator: defaultOption.EqualKeyValueSeparator,
-						Values:   []string{"val1"},
+						Key:      "all(intRoot-arrayRootPtr[0].intRoot)",
+						Operator: defaultOption.GreaterThanKeyValueSeparator,
+						Values:   []string{"3600"},
 					},
 				},
 				t: reflect.TypeOf(testStruct{}),
 			},
 			wantFilter: []kov{
 				{
-					Key:      "Matrix",
-					Operator: defaultOption.EqualKeyValueSeparator,
-					Values:   []string{"val1"},
+					Key:      "all((RootInt-RootArrayPtr[0].RootInt))",
+					Operator: defaultOption.GreaterThanKeyValueSeparator,
+					Values:   []string{"3600"},
 				},
 			},
 			wantErr: false,
 		},
 		{
-			name: "Filter in array values on Tag",
+			name: "Greater than on bool",
 			fields: fields{
 				options: defaultOption,
 				filter:  nil,
@@ -888,21 +2333,15 @@ func TestFilter_compileFilter(t *testing.T) {
 			args: args{
 				filterMap: []kov{
 					{
-						Key:      "arrayRoot",
-						Operator: defaultOption.EqualKeyValueSeparator,
-						Values:   []string{"val1"},
+						Key:      "boolRoot",
+						Operator: defaultOption.GreaterThanKeyValueSeparator,
+						Values:   []string{"true"},
 					},
 				},
 				t: reflect.TypeOf(testStruct{}),
 			},
-			wantFilter: []kov{
-				{
-					Key:      "RootArray",
-					Operator: defaultOption.EqualKeyValueSeparator,
-					Values:   []string{"val1"},
-				},
-			},
-			wantErr: false,
+			wantFilter: []kov{},
+			wantErr:    true,
 		},
 	}
 	for _, tt := range tests {
@@ -1457,22 +2896,64 @@ func TestFilter_parseFilter(t *testing.T) {
 			wantFilterMap: []kov{
 				{
 					Key:      "key1",
-					Operator: defaultOption.NotEqualKeyValueSeparator,
-					Values:   []string{"val1", "val2", "val3"},
+					Operator: defaultOption.NotEqualKeyValueSeparator,
+					Values:   []string{"val1", "val2", "val3"},
+				},
+				{
+					Key:      "key2",
+					Operator: defaultOption.LowerThanKeyValueSeparator,
+					Values:   []string{"4.5"},
+				},
+				{
+					Key:      "key3",
+					Operator: defaultOption.GreaterThanKeyValueSeparator,
+					Values:   []string{"-5"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "predicates",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil, //always null at parsing time
+			},
+			args: args{filterValue: "exists(key1):missing(key2.sub):null(key3):notnull(key3):key3=val1"},
+			wantFilterMap: []kov{
+				{
+					Key:      "key1",
+					Operator: existsPredicate,
 				},
 				{
-					Key:      "key2",
-					Operator: defaultOption.LowerThanKeyValueSeparator,
-					Values:   []string{"4.5"},
+					Key:      "key2.sub",
+					Operator: missingPredicate,
 				},
 				{
 					Key:      "key3",
-					Operator: defaultOption.GreaterThanKeyValueSeparator,
-					Values:   []string{"-5"},
+					Operator: nullPredicate,
+				},
+				{
+					Key:      "key3",
+					Operator: notNullPredicate,
+				},
+				{
+					Key:      "key3",
+					Operator: defaultOption.EqualKeyValueSeparator,
+					Values:   []string{"val1"},
 				},
 			},
 			wantErr: false,
 		},
+		{
+			name: "Wrong filter: unknown predicate",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil, //always null at parsing time
+			},
+			args:          args{filterValue: "unknown(key1)"},
+			wantFilterMap: nil,
+			wantErr:       true,
+		},
 		{
 			name: "Wrong filter: no values",
 			fields: fields{
@@ -1550,25 +3031,193 @@ func TestFilter_parseFilter(t *testing.T) {
 			wantErr:       true,
 		},
 		{
-			name: "Wrong filter: GT no numeric values",
+			name: "GT no numeric values, checked at compile time",
 			fields: fields{
 				options: defaultOption,
 				filter:  nil, //always null at parsing time
 			},
-			args:          args{filterValue: "k1" + defaultOption.GreaterThanKeyValueSeparator + "v1"},
-			wantFilterMap: nil,
-			wantErr:       true,
+			args: args{filterValue: "k1" + defaultOption.GreaterThanKeyValueSeparator + "v1"},
+			wantFilterMap: []kov{
+				{
+					Key:      "k1",
+					Operator: defaultOption.GreaterThanKeyValueSeparator,
+					Values:   []string{"v1"},
+				},
+			},
+			wantErr: false,
 		},
 		{
-			name: "Wrong filter: LT no numeric values",
+			name: "element match",
 			fields: fields{
 				options: defaultOption,
 				filter:  nil, //always null at parsing time
 			},
-			args:          args{filterValue: "k1" + defaultOption.LowerThanKeyValueSeparator + "v2"},
+			args: args{filterValue: "k1{k2=v1,v2:k3{k4>v3}}:k1{exists(k2)}:k5=[v4..v5)"},
+			wantFilterMap: []kov{
+				{
+					Key:      "k1",
+					Operator: elemMatchOperator,
+					Sub: []kov{
+						{
+							Key:      "k2",
+							Operator: defaultOption.EqualKeyValueSeparator,
+							Values:   []string{"v1", "v2"},
+						},
+						{
+							Key:      "k3",
+							Operator: elemMatchOperator,
+							Sub: []kov{
+								{
+									Key:      "k4",
+									Operator: defaultOption.GreaterThanKeyValueSeparator,
+									Values:   []string{"v3"},
+								},
+							},
+						},
+					},
+				},
+				{
+					Key:      "k1",
+					Operator: elemMatchOperator,
+					Sub: []kov{
+						{
+							Key:      "k2",
+							Operator: existsPredicate,
+						},
+					},
+				},
+				{
+					Key:      "k5",
+					Operator: defaultOption.EqualKeyValueSeparator,
+					Values:   []string{"[v4..v5)"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "element match without sub filter",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil, //always null at parsing time
+			},
+			args:          args{filterValue: "k1{}"},
 			wantFilterMap: nil,
 			wantErr:       true,
 		},
+		{
+			name: "braces in value",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil, //always null at parsing time
+			},
+			args: args{filterValue: "k1={v1}"},
+			wantFilterMap: []kov{
+				{
+					Key:      "k1",
+					Operator: defaultOption.EqualKeyValueSeparator,
+					Values:   []string{"{v1}"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "array range selector",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil, //always null at parsing time
+			},
+			args: args{filterValue: "k1[0:3].k2=v1:k1[-1]>v2"},
+			wantFilterMap: []kov{
+				{
+					Key:      "k1[0:3].k2",
+					Operator: defaultOption.EqualKeyValueSeparator,
+					Values:   []string{"v1"},
+				},
+				{
+					Key:      "k1[-1]",
+					Operator: defaultOption.GreaterThanKeyValueSeparator,
+					Values:   []string{"v2"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "insensitive modifier",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil, //always null at parsing time
+			},
+			args: args{filterValue: "k1=~v1,v2:k1!=~v3:k2>~v4:k2<v5"},
+			wantFilterMap: []kov{
+				{
+					Key:         "k1",
+					Operator:    defaultOption.EqualKeyValueSeparator,
+					Values:      []string{"v1", "v2"},
+					Insensitive: true,
+				},
+				{
+					Key:         "k1",
+					Operator:    defaultOption.NotEqualKeyValueSeparator,
+					Values:      []string{"v3"},
+					Insensitive: true,
+				},
+				{
+					Key:         "k2",
+					Operator:    defaultOption.GreaterThanKeyValueSeparator,
+					Values:      []string{"v4"},
+					Insensitive: true,
+				},
+				{
+					Key:      "k2",
+					Operator: defaultOption.LowerThanKeyValueSeparator,
+					Values:   []string{"v5"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "string operators",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil, //always null at parsing time
+			},
+			args: args{filterValue: "k1*=v1,v2:k1^=~v3:k1$=v4"},
+			wantFilterMap: []kov{
+				{
+					Key:      "k1",
+					Operator: defaultOption.ContainsKeyValueSeparator,
+					Values:   []string{"v1", "v2"},
+				},
+				{
+					Key:         "k1",
+					Operator:    defaultOption.StartsWithKeyValueSeparator,
+					Values:      []string{"v3"},
+					Insensitive: true,
+				},
+				{
+					Key:      "k1",
+					Operator: defaultOption.EndsWithKeyValueSeparator,
+					Values:   []string{"v4"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "LT no numeric values, checked at compile time",
+			fields: fields{
+				options: defaultOption,
+				filter:  nil, //always null at parsing time
+			},
+			args: args{filterValue: "k1" + defaultOption.LowerThanKeyValueSeparator + "v2"},
+			wantFilterMap: []kov{
+				{
+					Key:      "k1",
+					Operator: defaultOption.LowerThanKeyValueSeparator,
+					Values:   []string{"v2"},
+				},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1630,16 +3279,210 @@ func Test_foundFieldInStruct(t *testing.T) {
 			},
 			wantFieldName: nil,
 		},
+		{
+			name: "hidden by filter tag",
+			args: args{
+				filterKey: "cost",
+				t:         reflect.TypeOf(testTagStruct{}),
+			},
+			wantFieldName: nil,
+		},
+		{
+			name: "public name of filter tag",
+			args: args{
+				filterKey: "title",
+				t:         reflect.TypeOf(testTagStruct{}),
+			},
+			wantFieldName: getField(reflect.TypeOf(testTagStruct{}), 3),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{
+				options: defaultOption,
+			}
+			gotFieldName, err := f.foundFieldInStruct(tt.args.filterKey, tt.args.t)
+			if err != nil {
+				t.Fatalf("foundFieldInStruct() error = %v", err)
+			}
+			if !reflect.DeepEqual(gotFieldName, tt.wantFieldName) {
+				t.Errorf("foundFieldInStruct() = %v, want %v", gotFieldName, tt.wantFieldName)
+			}
+		})
+	}
+}
+
+func TestFilter_foundFieldInStruct_tagNames(t *testing.T) {
+	type tagNamesStruct struct {
+		CreatedAt string `firestore:"created_at" json:"createdAt,omitempty"`
+		Name      string `json:"name"`
+		Other     string `firestore:"name"`
+		Skipped   string `firestore:"-" json:"skipped"`
+	}
+	st := reflect.TypeOf(tagNamesStruct{})
+	tests := []struct {
+		name          string
+		tagNames      []string
+		filterKey     string
+		wantFieldName *reflect.StructField
+	}{
+		{
+			name:          "first tag",
+			tagNames:      []string{"firestore", "json"},
+			filterKey:     "created_at",
+			wantFieldName: getField(st, 0),
+		},
+		{
+			name:          "second tag",
+			tagNames:      []string{"firestore", "json"},
+			filterKey:     "createdAt",
+			wantFieldName: getField(st, 0),
+		},
+		{
+			name:          "struct field name",
+			tagNames:      []string{"firestore", "json"},
+			filterKey:     "CreatedAt",
+			wantFieldName: getField(st, 0),
+		},
+		{
+			name:          "first tag has priority on the other fields",
+			tagNames:      []string{"firestore", "json"},
+			filterKey:     "name",
+			wantFieldName: getField(st, 2),
+		},
+		{
+			name:          "tag order",
+			tagNames:      []string{"json", "firestore"},
+			filterKey:     "name",
+			wantFieldName: getField(st, 1),
+		},
+		{
+			name:          "skipped tag",
+			tagNames:      []string{"firestore", "json"},
+			filterKey:     "skipped",
+			wantFieldName: getField(st, 3),
+		},
+		{
+			name:          "not searched tag",
+			tagNames:      []string{"json"},
+			filterKey:     "created_at",
+			wantFieldName: nil,
+		},
+		{
+			name:          "no partial match",
+			tagNames:      []string{"json"},
+			filterKey:     "created",
+			wantFieldName: nil,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if gotFieldName := foundFieldInStruct(tt.args.filterKey, tt.args.t); !reflect.DeepEqual(gotFieldName, tt.wantFieldName) {
+			o := *defaultOption
+			o.TagNames = tt.tagNames
+			f := &Filter{
+				options: &o,
+			}
+			gotFieldName, err := f.foundFieldInStruct(tt.filterKey, st)
+			if err != nil {
+				t.Fatalf("foundFieldInStruct() error = %v", err)
+			}
+			if !reflect.DeepEqual(gotFieldName, tt.wantFieldName) {
 				t.Errorf("foundFieldInStruct() = %v, want %v", gotFieldName, tt.wantFieldName)
 			}
 		})
 	}
 }
 
+func TestFilter_caseInsensitiveKeys(t *testing.T) {
+	type caseStruct struct {
+		Name   string            `json:"name"`
+		Status string            `json:"status"`
+		STATUS string            `json:"STATUS"`
+		Labels map[string]string `json:"labels"`
+	}
+	entries := []caseStruct{
+		{Name: "n1", Status: "open", STATUS: "OPEN", Labels: map[string]string{"Env": "prod"}},
+		{Name: "n2", Status: "closed", STATUS: "CLOSED", Labels: map[string]string{"env": "dev", "ENV": "prod"}},
+	}
+	tests := []struct {
+		name        string
+		insensitive bool
+		filter      string
+		want        []caseStruct
+		wantErr     bool
+	}{
+		{
+			name:        "case sensitive keys",
+			insensitive: false,
+			filter:      "NAME=n1",
+			wantErr:     true,
+		},
+		{
+			name:        "case insensitive json tag",
+			insensitive: true,
+			filter:      "NAME=n1",
+			want:        entries[:1],
+		},
+		{
+			name:        "case insensitive field name",
+			insensitive: true,
+			filter:      "nAmE=n2",
+			want:        entries[1:],
+		},
+		{
+			name:        "exact match before ambiguity",
+			insensitive: true,
+			filter:      "STATUS=OPEN",
+			want:        entries[:1],
+		},
+		{
+			name:        "ambiguous key",
+			insensitive: true,
+			filter:      "sTaTuS=open",
+			wantErr:     true,
+		},
+		{
+			name:        "case insensitive map key",
+			insensitive: true,
+			filter:      "labels.ENV=prod",
+			want:        entries,
+		},
+		{
+			name:        "ambiguous map key ignored",
+			insensitive: true,
+			filter:      "labels.Env=dev,prod",
+			want:        entries[:1],
+		},
+		{
+			name:        "case sensitive map key",
+			insensitive: false,
+			filter:      "labels.env=prod",
+			want:        []caseStruct{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := *defaultOption
+			o.CaseInsensitiveKeys = tt.insensitive
+			f := &Filter{}
+			f.SetOptions(&o)
+			if err := f.Init(tt.filter, caseStruct{}); (err != nil) != tt.wantErr {
+				t.Fatalf("Init() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got, err := f.ApplyFilter(entries)
+			if err != nil {
+				t.Fatalf("ApplyFilter() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ApplyFilter() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 // Impossible to get the address of a struct field, but only of var.
 // This function is mandatory for test
 func getField(t reflect.Type, i int) *reflect.StructField {
@@ -1663,10 +3506,14 @@ type testStruct struct {
 	RootMap              map[string]SubStruct   `json:"mapRoot,omitempty"`
 	RootMapSimple        map[string]string      `json:"mapRootString,omitempty"`
 	RootMapArrayOfSimple map[string][]string    `json:"mapRootArrayOfString,omitempty"`
-	RootMapArrayOfStruct map[string][]SubStruct `json:"mapRootArrayOfString,omitempty"`
+	RootMapArrayOfStruct map[string][]SubStruct `json:"mapRootArrayOfStruct,omitempty"`
 	RootArrayPtr         []*testStruct          `json:"arrayRootPtr,omitempty"`
 	RootMapPtr           map[string]*testStruct `json:"mapRootPtr,omitempty"`
 	Matrix               [][]string             `json:"matrix,omitempty"`
+	RootTime             time.Time              `json:"timeRoot,omitempty"`
+	RootPtrTime          *time.Time             `json:"ptrTimeRoot,omitempty"`
+	RootDuration         time.Duration          `json:"durationRoot,omitempty"`
+	RootMapInt           map[int]string         `json:"mapRootInt,omitempty"`
 }
 
 func TestFilter_getFilterAndValue(t *testing.T) {
@@ -1746,3 +3593,128 @@ func TestFilter_getFilterAndValue(t *testing.T) {
 		})
 	}
 }
+
+func Test_splitOutsideBrackets(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		sep  string
+		want []string
+	}{
+		{
+			name: "no bracket",
+			s:    "k1=v1:k2=v2",
+			sep:  ":",
+			want: []string{"k1=v1", "k2=v2"},
+		},
+		{
+			name: "separator in braces",
+			s:    "k1{k2=v1:k3=v2}:k4=v3",
+			sep:  ":",
+			want: []string{"k1{k2=v1:k3=v2}", "k4=v3"},
+		},
+		{
+			name: "separator in brackets and parenthesis",
+			s:    "k1=[v1,v2),v3",
+			sep:  ",",
+			want: []string{"k1=[v1,v2)", "v3"},
+		},
+		{
+			name: "multi characters separator",
+			s:    "k1{k2=v1::k3=v2}::k4=v3",
+			sep:  "::",
+			want: []string{"k1{k2=v1::k3=v2}", "k4=v3"},
+		},
+		{
+			name: "not balanced",
+			s:    "k1=v1(:k2=v2",
+			sep:  ":",
+			want: []string{"k1=v1(", "k2=v2"},
+		},
+		{
+			name: "separator in time values",
+			s:    "k1>2020-01-31T10:00:00Z:k2=2020-01-31T11:00:00.5+01:00,2020-02-01:k3=v3",
+			sep:  ":",
+			want: []string{"k1>2020-01-31T10:00:00Z", "k2=2020-01-31T11:00:00.5+01:00,2020-02-01", "k3=v3"},
+		},
+		{
+			name: "separator in time values not balanced",
+			s:    "k1=v1(:k2<2020-01-31T10:00:00Z",
+			sep:  ":",
+			want: []string{"k1=v1(", "k2<2020-01-31T10:00:00Z"},
+		},
+		{
+			name: "empty",
+			s:    "",
+			sep:  ":",
+			want: []string{""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitOutsideBrackets(tt.s, tt.sep); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitOutsideBrackets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter_emptyPrefixOptions(t *testing.T) {
+	entries := []testStruct{
+		{RootString: "$5"},
+		{RootString: "~x"},
+		{RootString: "X"},
+	}
+	tests := []struct {
+		name     string
+		prefix   string
+		modifier string
+		filter   string
+		want     []testStruct
+	}{
+		{
+			name:     "literal value without reference prefix",
+			modifier: "~",
+			filter:   "stringRoot=$5",
+			want:     entries[:1],
+		},
+		{
+			name:     "literal value with doubled prefix without reference prefix",
+			modifier: "~",
+			filter:   "stringRoot!=$$5",
+			want:     entries,
+		},
+		{
+			name:   "literal value without insensitive modifier",
+			prefix: "$",
+			filter: "stringRoot=~x",
+			want:   entries[1:2],
+		},
+		{
+			name:     "insensitive modifier",
+			prefix:   "$",
+			modifier: "~",
+			filter:   "stringRoot=~x",
+			want:     entries[2:],
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := *defaultOption
+			o.FieldReferencePrefix = tt.prefix
+			o.InsensitiveModifier = tt.modifier
+			f := &Filter{}
+			f.SetOptions(&o)
+			if err := f.Init(tt.filter, testStruct{}); err != nil {
+				t.Fatalf("Init() error = %v", err)
+			}
+			got, err := f.ApplyFilter(entries)
+			if err != nil {
+				t.Fatalf("ApplyFilter() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ApplyFilter() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package jsonFilter
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+)
+
+// Check if the compiled composed key is allowed by the allowed and the denied keys of the options. The keys of the
+// element match sub filters are prefixed by the key of the element match. A key which isn't allowed raises the same
+// error as a key which doesn't exist, with the key as provided in the filter.
+// Return an error if the key isn't allowed or if a key pattern of the options is invalid. The keys compiled outside
+// of a filter aren't checked
+func (f *Filter) checkKeyAccess(ck string, k string) error {
+	if f.keyRoot == nil {
+		return nil
+	}
+	allowed, err := f.isKeyAllowed(f.keyPrefix+ck, f.keyRoot)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return keyNotExistError(k)
+	}
+	return nil
+}
+
+// Get the error of a filter key which doesn't exist. The keys which aren't allowed raise the same error, to not reveal
+// that they exist
+func keyNotExistError(k string) error {
+	return errors.New(fmt.Sprintf("The Filter key %s not exist in the returned object", k))
+}
+
+// Check if the compiled composed key, from the root type, is allowed: matching an allowed key, if any, and no denied
+// key.
+// Return an error if a key pattern of the options is invalid
+func (f *Filter) isKeyAllowed(ck string, t reflect.Type) (bool, error) {
+	denied, err := f.matchKeyPatterns(ck, t, f.options.DeniedKeys)
+	if err != nil {
+		return false, err
+	}
+	allowed := len(f.options.AllowedKeys) == 0
+	if !allowed {
+		if allowed, err = f.matchKeyPatterns(ck, t, f.options.AllowedKeys); err != nil {
+			return false, err
+		}
+	}
+	return allowed && !denied, nil
+}
+
+// Check if the compiled composed key matches at least one key pattern. A pattern matches the key, like
+// RootStruct.SubString, or the beginning of the key, like RootStruct. Each part of the pattern can have wildcards, like
+// Root* or *.SubString. The array selectors of the key are ignored. The struct field names of the key are compared
+// exactly, the map keys are compared case insensitively with the case insensitive keys, like when they are found.
+// Return an error if a pattern is invalid
+func (f *Filter) matchKeyPatterns(ck string, t reflect.Type, patterns []string) (bool, error) {
+	var kps []string
+	for _, p := range strings.Split(ck, f.options.ComposedKeySeparator) {
+		// The key part has been validated at compile time
+		name, _, _ := parseKeyPart(p)
+		kps = append(kps, name)
+	}
+	mks := f.mapKeyParts(kps, t)
+
+	for _, pattern := range patterns {
+		pps := strings.Split(pattern, f.options.ComposedKeySeparator)
+		if len(pps) > len(kps) {
+			continue
+		}
+		m := true
+		for i, pp := range pps {
+			kp := kps[i]
+			if mks[i] && f.options.CaseInsensitiveKeys {
+				pp, kp = strings.ToLower(pp), strings.ToLower(kp)
+			}
+			pm, err := path.Match(pp, kp)
+			if err != nil {
+				return false, errors.New(fmt.Sprintf("The key pattern %s of the options is invalid: %s", pattern, err))
+			}
+			if !pm {
+				m = false
+				break
+			}
+		}
+		if m {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Find the parts of the compiled composed key which are map keys, the other parts being struct field names or virtual
+// fields
+func (f *Filter) mapKeyParts(kps []string, t reflect.Type) []bool {
+	mks := make([]bool, len(kps))
+	for i, name := range kps {
+		for t.Kind() == reflect.Slice || t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		switch t.Kind() {
+		case reflect.Map:
+			mks[i] = true
+			t = t.Elem()
+			continue
+		case reflect.Struct:
+			if sf, ok := t.FieldByName(name); ok {
+				t = sf.Type
+				continue
+			}
+			if vf, ok := f.findVirtualField(name, t); ok {
+				t = vf.resultType
+				continue
+			}
+		}
+		break
+	}
+	return mks
+}
@@ -0,0 +1,266 @@
+package jsonFilter
+
+import (
+	"testing"
+)
+
+func TestFilter_checkKeysAccess(t *testing.T) {
+	tests := []struct {
+		name        string
+		allowed     []string
+		denied      []string
+		insensitive bool
+		filter      string
+		wantErr     bool
+	}{
+		{
+			name:   "no allowed and denied keys",
+			filter: "stringRoot=v1:intRoot>1",
+		},
+		{
+			name:    "allowed key",
+			allowed: []string{"RootString"},
+			filter:  "stringRoot=v1",
+		},
+		{
+			name:    "not allowed key",
+			allowed: []string{"RootString"},
+			filter:  "intRoot=1",
+			wantErr: true,
+		},
+		{
+			name:    "allowed sub key",
+			allowed: []string{"RootStruct"},
+			filter:  "structRoot.stringSub=v1",
+		},
+		{
+			name:    "not allowed parent key",
+			allowed: []string{"RootStruct.SubString"},
+			filter:  "len(structRoot)>1",
+			wantErr: true,
+		},
+		{
+			name:    "allowed with wildcard",
+			allowed: []string{"Root*"},
+			filter:  "intRoot=1:stringRoot=v1",
+		},
+		{
+			name:    "not allowed with wildcard",
+			allowed: []string{"Root*"},
+			filter:  "matrix=v1",
+			wantErr: true,
+		},
+		{
+			name:    "allowed with wildcard part and array selector",
+			allowed: []string{"*.SubString"},
+			filter:  "arrayRoot[0].stringSub=v1",
+		},
+		{
+			name:    "denied key",
+			denied:  []string{"RootInt"},
+			filter:  "intRoot>1",
+			wantErr: true,
+		},
+		{
+			name:    "denied key wins on allowed key",
+			allowed: []string{"RootArray"},
+			denied:  []string{"RootArray.SubString"},
+			filter:  "arrayRoot.stringSub=v1",
+			wantErr: true,
+		},
+		{
+			name:   "denied key not used",
+			denied: []string{"RootInt"},
+			filter: "len(stringRoot)>1",
+		},
+		{
+			name:    "denied key in function",
+			denied:  []string{"RootString"},
+			filter:  "len(stringRoot)>1",
+			wantErr: true,
+		},
+		{
+			name:    "denied key in expression",
+			denied:  []string{"RootFloat"},
+			filter:  "intRoot*floatRoot>1",
+			wantErr: true,
+		},
+		{
+			name:    "denied referenced key",
+			denied:  []string{"RootFloat"},
+			filter:  "intRoot>$floatRoot",
+			wantErr: true,
+		},
+		{
+			name:    "denied key in element match",
+			denied:  []string{"RootArray.SubString"},
+			filter:  "arrayRoot{stringSub=v1}",
+			wantErr: true,
+		},
+		{
+			name:    "allowed key with element match",
+			allowed: []string{"RootArray"},
+			filter:  "arrayRoot{stringSub=v1}",
+		},
+		{
+			name:    "denied map key",
+			denied:  []string{"RootMapSimple.secret"},
+			filter:  "mapRootString.secret=v1",
+			wantErr: true,
+		},
+		{
+			name:        "denied map key case insensitively",
+			denied:      []string{"RootMapSimple.secret"},
+			insensitive: true,
+			filter:      "mapRootString.SECRET=v1",
+			wantErr:     true,
+		},
+		{
+			name:    "invalid key pattern",
+			denied:  []string{"Root["},
+			filter:  "stringRoot=v1",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := *defaultOption
+			o.AllowedKeys = tt.allowed
+			o.DeniedKeys = tt.denied
+			o.CaseInsensitiveKeys = tt.insensitive
+			f := &Filter{}
+			f.SetOptions(&o)
+			if err := f.Init(tt.filter, testStruct{}); (err != nil) != tt.wantErr {
+				t.Errorf("Init() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFilter_checkKeysAccess_partialFilter(t *testing.T) {
+	o := *defaultOption
+	o.DeniedKeys = []string{"RootInt"}
+	f := &Filter{}
+	f.SetOptions(&o)
+	if err := f.Init("stringRoot=v1:intRoot=1:boolRoot=true", testStruct{}); err == nil {
+		t.Fatalf("Init() error = nil, want denied key error")
+	}
+	if len(f.filter) != 1 || f.filter[0].Key != "RootString" {
+		t.Errorf("Init() filter = %v, want only the filter before the denied one", f.filter)
+	}
+}
+
+func TestFilter_checkKeysAccess_caseInsensitiveKeys(t *testing.T) {
+	type entry struct {
+		Name  string            `json:"name"`
+		NAME  string            `json:"other"`
+		Attrs map[string]string `json:"attrs"`
+	}
+	tests := []struct {
+		name    string
+		allowed []string
+		denied  []string
+		filter  string
+		wantErr bool
+	}{
+		{
+			name:    "allowed field",
+			allowed: []string{"Name"},
+			filter:  "name=x",
+		},
+		{
+			name:    "other field with the same name case insensitively",
+			allowed: []string{"Name"},
+			filter:  "NAME=x",
+			wantErr: true,
+		},
+		{
+			name:    "other field by its json tag",
+			allowed: []string{"Name"},
+			filter:  "other=x",
+			wantErr: true,
+		},
+		{
+			name:    "pattern compared exactly to the field name",
+			allowed: []string{"name"},
+			filter:  "name=x",
+			wantErr: true,
+		},
+		{
+			name:    "denied map key case insensitively",
+			denied:  []string{"Attrs.secret"},
+			filter:  "attrs.SECRET=x",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := *defaultOption
+			o.AllowedKeys = tt.allowed
+			o.DeniedKeys = tt.denied
+			o.CaseInsensitiveKeys = true
+			f := &Filter{}
+			f.SetOptions(&o)
+			if err := f.Init(tt.filter, entry{}); (err != nil) != tt.wantErr {
+				t.Errorf("Init() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFilter_checkKeysAccess_notExistError(t *testing.T) {
+	tests := []struct {
+		name    string
+		denied  []string
+		filter  string
+		wantKey string
+	}{
+		{
+			name:    "unknown key",
+			filter:  "unknown=1",
+			wantKey: "unknown",
+		},
+		{
+			name:    "denied key",
+			denied:  []string{"RootInt"},
+			filter:  "intRoot=1",
+			wantKey: "intRoot",
+		},
+		{
+			name:    "denied key with invalid value",
+			denied:  []string{"RootInt"},
+			filter:  "intRoot=abc",
+			wantKey: "intRoot",
+		},
+		{
+			name:    "denied key in function",
+			denied:  []string{"RootString"},
+			filter:  "len(stringRoot)>1",
+			wantKey: "stringRoot",
+		},
+		{
+			name:    "denied referenced key not comparable",
+			denied:  []string{"RootInt"},
+			filter:  "stringRoot=$intRoot",
+			wantKey: "intRoot",
+		},
+		{
+			name:    "denied key in element match",
+			denied:  []string{"RootArray.SubString"},
+			filter:  "arrayRoot{stringSub=v1}",
+			wantKey: "stringSub",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := *defaultOption
+			o.DeniedKeys = tt.denied
+			f := &Filter{}
+			f.SetOptions(&o)
+			err := f.Init(tt.filter, testStruct{})
+			if err == nil || err.Error() != keyNotExistError(tt.wantKey).Error() {
+				t.Errorf("Init() error = %v, want %v", err, keyNotExistError(tt.wantKey))
+			}
+		})
+	}
+}
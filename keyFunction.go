@@ -0,0 +1,289 @@
+package jsonFilter
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// Functions applicable on a key, like len(key). The result of the function is compared to the filter values
+const (
+	// Number of elements of the string, array or map of the key
+	lenFunction = "len"
+	// Keys of the map of the key
+	keysFunction = "keys"
+)
+
+// Scalar functions applicable on a key, like lower(key). The function is applied on each value of the key
+const (
+	// Lower case of the string values
+	lowerFunction = "lower"
+	// Upper case of the string values
+	upperFunction = "upper"
+	// String values without leading and trailing spaces
+	trimFunction = "trim"
+	// Year of the time values, in UTC
+	yearFunction = "year"
+	// Absolute value of the numeric values
+	absFunction = "abs"
+	// Nearest integer of the numeric values, rounding half away from zero
+	roundFunction = "round"
+)
+
+// Quantifiers applicable on a key, like all(key). They define how many values of the key must match the filter values
+const (
+	// At least one value must match
+	anyQuantifier = "any"
+	// All the values must match. Match if there is no value
+	allQuantifier = "all"
+	// No value must match. Match if there is no value
+	noneQuantifier = "none"
+)
+
+var (
+	intType    = reflect.TypeOf(0)
+	stringType = reflect.TypeOf("")
+)
+
+func isQuantifier(fn string) bool {
+	return fn == anyQuantifier || fn == allQuantifier || fn == noneQuantifier
+}
+
+// Get the quantifier and the key of the filter. Without explicit quantifier, like all(key), the default quantifier of
+// the operator is returned: all for the not equal operator (no value must be equal), any for the others
+func getQuantifierAndKey(kov kov, o *Options) (q string, k string) {
+	if fn, arg, ok := getFunctionAndArgument(kov.Key); ok && isQuantifier(fn) {
+		return fn, arg
+	}
+	if kov.Operator == o.NotEqualKeyValueSeparator {
+		return allQuantifier, kov.Key
+	}
+	return anyQuantifier, kov.Key
+}
+
+// Check if the values of the entry match, according with the quantifier
+func matchQuantifier(q string, evl []reflect.Value, match func(ev reflect.Value) bool) bool {
+	for _, ev := range evl {
+		m := match(ev)
+		switch {
+		case q == anyQuantifier && m:
+			return true
+		case q == allQuantifier && !m:
+			return false
+		case q == noneQuantifier && m:
+			return false
+		}
+	}
+	return q != anyQuantifier
+}
+
+// Find the struct field names in relation with the key provided in the query, which can be wrapped in a quantifier,
+// like all(key).
+// Return the key with the struct field names, and the type of the key values
+func (f *Filter) compileQuantifiedKey(k string, t reflect.Type) (string, reflect.Type, error) {
+	if fn, arg, ok := getFunctionAndArgument(k); ok && isQuantifier(fn) {
+		ca, at, err := f.compileKey(arg, t)
+		if err != nil {
+			return "", nil, err
+		}
+		return fn + "(" + ca + ")", at, nil
+	}
+	return f.compileKey(k, t)
+}
+
+// Get the function name and the argument of a function key, like len(key).
+// Return false if the key isn't a function
+func getFunctionAndArgument(k string) (fn string, arg string, ok bool) {
+	i := strings.Index(k, "(")
+	if i <= 0 || !strings.HasSuffix(k, ")") {
+		return
+	}
+	for _, r := range k[:i] {
+		if !unicode.IsLetter(r) {
+			return
+		}
+	}
+	// The opening parenthesis must be closed at the end of the key, not before, like in len(a).len(b)
+	depth := 0
+	for j, r := range k[i:] {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 && i+j != len(k)-1 {
+				return
+			}
+		}
+	}
+	return k[:i], k[i+1 : len(k)-1], true
+}
+
+// Find the struct field names in relation with the key provided in the query, which can be a function on a composed
+// key, like len(key), or an arithmetic expression, like price*quantity.
+// Return the key with the struct field names, and the type of the key values
+func (f *Filter) compileKey(k string, t reflect.Type) (string, reflect.Type, error) {
+	fn, arg, ok := getFunctionAndArgument(k)
+	if !ok {
+		ck, ct, err := f.compileComposedKey(k, t)
+		// If the key isn't found, it can be an arithmetic expression, like price*quantity
+		if err != nil && strings.ContainsAny(k, arithmeticOperators+"(") {
+			return f.compileExpression(k, t)
+		}
+		return ck, ct, err
+	}
+
+	if isQuantifier(fn) {
+		return "", nil, errors.New(fmt.Sprintf("The quantifier %s must wrap the whole Filter key and can't be used in %s", fn, k))
+	}
+
+	switch fn {
+	case lenFunction:
+		ca, at, err := f.compileComposedKey(arg, t)
+		if err != nil {
+			return "", nil, err
+		}
+		// The length is evaluated on the container, before flattening it
+		if !isContainerType(at) {
+			return "", nil, errors.New(fmt.Sprintf("The Filter key %s isn't a string, an array or a map and can't be used in the function %s", arg, fn))
+		}
+		return fn + "(" + ca + ")", intType, nil
+	case keysFunction:
+		ca, at, err := f.compileComposedKey(arg, t)
+		if err != nil {
+			return "", nil, err
+		}
+		// The keys of the maps in array are all evaluated, like with the values
+		if mt := leafType(at); mt.Kind() == reflect.Map {
+			return fn + "(" + ca + ")", mt.Key(), nil
+		}
+		return "", nil, errors.New(fmt.Sprintf("The Filter key %s isn't a map and can't be used in the function %s", arg, fn))
+	case lowerFunction, upperFunction, trimFunction, yearFunction, absFunction, roundFunction:
+		// The scalar functions can be applied on functions and expressions, like lower(trim(key))
+		ca, at, err := f.compileKey(arg, t)
+		if err != nil {
+			return "", nil, err
+		}
+		if rt, ok := scalarFunctionType(fn, leafType(at)); ok {
+			return fn + "(" + ca + ")", rt, nil
+		}
+		return "", nil, errors.New(fmt.Sprintf("The Filter key %s of type %s can't be used in the function %s", arg, leafType(at), fn))
+	}
+	return "", nil, errors.New(fmt.Sprintf("The Filter key %s uses the unknown function %s", k, fn))
+}
+
+// Find all values (leaf value) associated with a key (filter name). In case of function on the key, the function
+// result values are returned
+func (f *Filter) findValueInKey(k string, evs reflect.Value) []reflect.Value {
+	if isExpression(k) {
+		return f.findValueInExpression(k, evs)
+	}
+	fn, arg, ok := getFunctionAndArgument(k)
+	if !ok {
+		return f.findValueInComposedKey(k, evs)
+	}
+
+	r := make([]reflect.Value, 0) //result
+	switch fn {
+	case lenFunction:
+		// The length of the missing values (not existing map entry, nil pointer on the path) isn't evaluated
+		for _, cv := range f.findContainerInComposedKey(arg, evs) {
+			if l := containerLen(cv.value); l >= 0 {
+				r = append(r, reflect.ValueOf(l))
+			}
+		}
+	case lowerFunction, upperFunction, trimFunction, yearFunction, absFunction, roundFunction:
+		// The values which aren't of the function type (interface values) are ignored
+		for _, v := range f.findValueInKey(arg, evs) {
+			if rv, ok := applyScalarFunction(fn, v); ok {
+				r = append(r, rv)
+			}
+		}
+	case keysFunction:
+		// The maps are found like the leaf values, the nil pointers are ignored and the arrays are flattened
+		for _, m := range f.findValueInComposedKey(arg, evs) {
+			if m.Kind() == reflect.Map {
+				r = append(r, m.MapKeys()...)
+			}
+		}
+	}
+	return r
+}
+
+// Get the composed keys of the compiled key, in the functions and the expressions of the key, like price and quantity
+// in all((price*quantity))
+func (f *Filter) composedKeys(k string) []string {
+	if isExpression(k) {
+		e, ok := f.compiledExpression(k)
+		if !ok {
+			return nil
+		}
+		var cks []string
+		for _, ek := range e.keys() {
+			cks = append(cks, f.composedKeys(ek)...)
+		}
+		return cks
+	}
+	if _, arg, ok := getFunctionAndArgument(k); ok {
+		return f.composedKeys(arg)
+	}
+	return []string{k}
+}
+
+// Get the type of the result of the scalar function applied on the values of the type.
+// Return false if the function can't be applied on the type. The interface values are checked when the filter is
+// applied
+func scalarFunctionType(fn string, t reflect.Type) (reflect.Type, bool) {
+	isInterface := t.Kind() == reflect.Interface
+	switch fn {
+	case lowerFunction, upperFunction, trimFunction:
+		return stringType, isInterface || t.Kind() == reflect.String
+	case yearFunction:
+		return intType, isInterface || t == timeType
+	case absFunction, roundFunction:
+		return t, isInterface || isNumericKind(t.Kind())
+	}
+	return nil, false
+}
+
+// Apply the scalar function on the value. The numeric values keep their type, like a duration.
+// Return false if the function can't be applied on the value
+func applyScalarFunction(fn string, v reflect.Value) (reflect.Value, bool) {
+	v = concreteValue(v)
+	if !v.IsValid() {
+		return v, false
+	}
+
+	switch {
+	case v.Kind() == reflect.String && fn == lowerFunction:
+		return reflect.ValueOf(strings.ToLower(v.String())), true
+	case v.Kind() == reflect.String && fn == upperFunction:
+		return reflect.ValueOf(strings.ToUpper(v.String())), true
+	case v.Kind() == reflect.String && fn == trimFunction:
+		return reflect.ValueOf(strings.TrimSpace(v.String())), true
+	case v.Type() == timeType && fn == yearFunction:
+		vt, ok := timeValue(v)
+		return reflect.ValueOf(vt.UTC().Year()), ok
+	case fn != absFunction && fn != roundFunction:
+		return v, false
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i := v.Int(); i < 0 && fn == absFunction {
+			return reflect.ValueOf(-i).Convert(v.Type()), true
+		}
+		return v, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v, true
+	case reflect.Float32, reflect.Float64:
+		if fn == absFunction {
+			return reflect.ValueOf(math.Abs(v.Float())).Convert(v.Type()), true
+		}
+		return reflect.ValueOf(math.Round(v.Float())).Convert(v.Type()), true
+	}
+	return v, false
+}
@@ -0,0 +1,391 @@
+package jsonFilter
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_getFunctionAndArgument(t *testing.T) {
+	tests := []struct {
+		name    string
+		k       string
+		wantFn  string
+		wantArg string
+		wantOk  bool
+	}{
+		{
+			name:    "function on composed key",
+			k:       "len(key1.sub)",
+			wantFn:  "len",
+			wantArg: "key1.sub",
+			wantOk:  true,
+		},
+		{
+			name:    "nested functions",
+			k:       "len(len(key1))",
+			wantFn:  "len",
+			wantArg: "len(key1)",
+			wantOk:  true,
+		},
+		{
+			name:   "composed key",
+			k:      "key1.sub",
+			wantOk: false,
+		},
+		{
+			name:   "not closed at the end",
+			k:      "len(key1).len(key2)",
+			wantOk: false,
+		},
+		{
+			name:   "no function name",
+			k:      "(key1)",
+			wantOk: false,
+		},
+		{
+			name:   "not a function name",
+			k:      "key.len(key1)",
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFn, gotArg, gotOk := getFunctionAndArgument(tt.k)
+			if gotFn != tt.wantFn || gotArg != tt.wantArg || gotOk != tt.wantOk {
+				t.Errorf("getFunctionAndArgument() = %v, %v, %v, want %v, %v, %v", gotFn, gotArg, gotOk, tt.wantFn, tt.wantArg, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestFilter_compileKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		k        string
+		wantKey  string
+		wantType reflect.Type
+		wantErr  bool
+	}{
+		{
+			name:     "composed key",
+			k:        "structRoot.stringSub",
+			wantKey:  "RootStruct.SubString",
+			wantType: reflect.TypeOf(""),
+		},
+		{
+			name:     "len of array",
+			k:        "len(arrayRootSimple)",
+			wantKey:  "len(RootArraySimple)",
+			wantType: intType,
+		},
+		{
+			name:     "len of map in array",
+			k:        "len(arrayRootPtr.mapRootString)",
+			wantKey:  "len(RootArrayPtr.RootMapSimple)",
+			wantType: intType,
+		},
+		{
+			name:     "keys of map",
+			k:        "keys(mapRootString)",
+			wantKey:  "keys(RootMapSimple)",
+			wantType: reflect.TypeOf(""),
+		},
+		{
+			name:     "keys of maps in array",
+			k:        "keys(arrayRootPtr.mapRootPtr)",
+			wantKey:  "keys(RootArrayPtr.RootMapPtr)",
+			wantType: reflect.TypeOf(""),
+		},
+		{
+			name:    "keys of array",
+			k:       "keys(arrayRootSimple)",
+			wantErr: true,
+		},
+		{
+			name:     "lower of array",
+			k:        "lower(arrayRootSimple)",
+			wantKey:  "lower(RootArraySimple)",
+			wantType: stringType,
+		},
+		{
+			name:     "nested functions",
+			k:        "upper(trim(structRoot.stringSub))",
+			wantKey:  "upper(trim(RootStruct.SubString))",
+			wantType: stringType,
+		},
+		{
+			name:     "year of time pointer",
+			k:        "year(ptrTimeRoot)",
+			wantKey:  "year(RootPtrTime)",
+			wantType: intType,
+		},
+		{
+			name:     "abs of expression",
+			k:        "abs(intRoot-len(arrayRootSimple))",
+			wantKey:  "abs((RootInt-len(RootArraySimple)))",
+			wantType: int64Type,
+		},
+		{
+			name:     "round of float",
+			k:        "round(floatRoot)",
+			wantKey:  "round(RootFloat)",
+			wantType: reflect.TypeOf(float32(0)),
+		},
+		{
+			name:    "lower of numeric",
+			k:       "lower(intRoot)",
+			wantErr: true,
+		},
+		{
+			name:    "year of string",
+			k:       "year(stringRoot)",
+			wantErr: true,
+		},
+		{
+			name:    "abs of string",
+			k:       "abs(stringRoot)",
+			wantErr: true,
+		},
+		{
+			name:    "len of numeric",
+			k:       "len(intRoot)",
+			wantErr: true,
+		},
+		{
+			name:    "len of unknown key",
+			k:       "len(unknown)",
+			wantErr: true,
+		},
+		{
+			name:    "unknown function",
+			k:       "size(arrayRoot)",
+			wantErr: true,
+		},
+		{
+			name:    "quantifier inside the key",
+			k:       "len(all(arrayRootSimple))",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{
+				options: defaultOption,
+			}
+			gotKey, gotType, err := f.compileKey(tt.k, reflect.TypeOf(testStruct{}))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("compileKey() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotKey != tt.wantKey || gotType != tt.wantType {
+				t.Errorf("compileKey() = %v, %v, want %v, %v", gotKey, gotType, tt.wantKey, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestFilter_findValueInKey(t *testing.T) {
+	entry := reflect.ValueOf(testStruct{
+		RootString:      "value1",
+		RootArraySimple: []string{"val1", "val2"},
+		RootArrayPtr: []*testStruct{
+			{RootMapSimple: map[string]string{"entry1": "val1"}},
+			nil,
+			{RootMapSimple: map[string]string{"entry2": "val2"}},
+			{},
+		},
+		Matrix:     [][]string{{"AA", "AB"}, {"BA"}},
+		RootStruct: SubStruct{SubString: "été"},
+	})
+	tests := []struct {
+		name string
+		k    string
+		want []interface{}
+	}{
+		{
+			name: "composed key",
+			k:    "RootString",
+			want: []interface{}{"value1"},
+		},
+		{
+			name: "len of string",
+			k:    "len(RootString)",
+			want: []interface{}{6},
+		},
+		{
+			name: "len of non ASCII string, in characters",
+			k:    "len(RootStruct.SubString)",
+			want: []interface{}{3},
+		},
+		{
+			name: "len of array",
+			k:    "len(RootArraySimple)",
+			want: []interface{}{2},
+		},
+		{
+			name: "len of matrix, not flattened",
+			k:    "len(Matrix)",
+			want: []interface{}{2},
+		},
+		{
+			name: "len of maps in array, nil pointer ignored",
+			k:    "len(RootArrayPtr.RootMapSimple)",
+			want: []interface{}{1, 1, 0},
+		},
+		{
+			name: "len of nil array",
+			k:    "len(RootArray)",
+			want: []interface{}{0},
+		},
+		{
+			name: "keys of maps in array, nil map ignored",
+			k:    "keys(RootArrayPtr.RootMapSimple)",
+			want: []interface{}{"entry1", "entry2"},
+		},
+		{
+			name: "keys of nil map",
+			k:    "keys(RootMapSimple)",
+			want: []interface{}{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{
+				options: defaultOption,
+			}
+			got := f.findValueInKey(tt.k, entry)
+			if len(got) != len(tt.want) {
+				t.Fatalf("findValueInKey() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if fmt.Sprint(got[i]) != fmt.Sprint(tt.want[i]) {
+					t.Errorf("findValueInKey() = %v, want %v", got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFilter_compileQuantifiedKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		k        string
+		wantKey  string
+		wantType reflect.Type
+		wantErr  bool
+	}{
+		{
+			name:     "no quantifier",
+			k:        "arrayRootSimple",
+			wantKey:  "RootArraySimple",
+			wantType: reflect.TypeOf([]string{}),
+		},
+		{
+			name:     "quantifier on composed key",
+			k:        "all(structRoot.stringSub)",
+			wantKey:  "all(RootStruct.SubString)",
+			wantType: reflect.TypeOf(""),
+		},
+		{
+			name:     "quantifier on function",
+			k:        "none(len(arrayRootSimple))",
+			wantKey:  "none(len(RootArraySimple))",
+			wantType: intType,
+		},
+		{
+			name:    "quantifier on unknown key",
+			k:       "any(unknown)",
+			wantErr: true,
+		},
+		{
+			name:    "nested quantifiers",
+			k:       "all(none(arrayRootSimple))",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{
+				options: defaultOption,
+			}
+			gotKey, gotType, err := f.compileQuantifiedKey(tt.k, reflect.TypeOf(testStruct{}))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("compileQuantifiedKey() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotKey != tt.wantKey || gotType != tt.wantType {
+				t.Errorf("compileQuantifiedKey() = %v, %v, want %v, %v", gotKey, gotType, tt.wantKey, tt.wantType)
+			}
+		})
+	}
+}
+
+func Test_matchQuantifier(t *testing.T) {
+	positive := func(ev reflect.Value) bool {
+		return ev.Int() > 0
+	}
+	tests := []struct {
+		name string
+		q    string
+		evl  []int
+		want bool
+	}{
+		{name: "any with one match", q: anyQuantifier, evl: []int{-1, 1}, want: true},
+		{name: "any without match", q: anyQuantifier, evl: []int{-1, -2}, want: false},
+		{name: "any without value", q: anyQuantifier, want: false},
+		{name: "all with all matches", q: allQuantifier, evl: []int{1, 2}, want: true},
+		{name: "all with one mismatch", q: allQuantifier, evl: []int{1, -2}, want: false},
+		{name: "all without value", q: allQuantifier, want: true},
+		{name: "none without match", q: noneQuantifier, evl: []int{-1, -2}, want: true},
+		{name: "none with one match", q: noneQuantifier, evl: []int{-1, 2}, want: false},
+		{name: "none without value", q: noneQuantifier, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var evl []reflect.Value
+			for _, v := range tt.evl {
+				evl = append(evl, reflect.ValueOf(v))
+			}
+			if got := matchQuantifier(tt.q, evl, positive); got != tt.want {
+				t.Errorf("matchQuantifier() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_applyScalarFunction(t *testing.T) {
+	tests := []struct {
+		name   string
+		fn     string
+		v      interface{}
+		want   interface{}
+		wantOk bool
+	}{
+		{name: "lower", fn: lowerFunction, v: "OPEN", want: "open", wantOk: true},
+		{name: "upper", fn: upperFunction, v: "open", want: "OPEN", wantOk: true},
+		{name: "trim", fn: trimFunction, v: " open\n", want: "open", wantOk: true},
+		{name: "year in UTC", fn: yearFunction, v: time.Date(2021, 1, 1, 0, 30, 0, 0, time.FixedZone("", 3600)), want: 2020, wantOk: true},
+		{name: "abs of int", fn: absFunction, v: -3, want: 3, wantOk: true},
+		{name: "abs of duration", fn: absFunction, v: -time.Second, want: time.Second, wantOk: true},
+		{name: "abs of float32", fn: absFunction, v: float32(-1.5), want: float32(1.5), wantOk: true},
+		{name: "abs of uint", fn: absFunction, v: uint(3), want: uint(3), wantOk: true},
+		{name: "round half away from zero", fn: roundFunction, v: -2.5, want: -3.0, wantOk: true},
+		{name: "round of int", fn: roundFunction, v: 3, want: 3, wantOk: true},
+		{name: "lower of int", fn: lowerFunction, v: 3},
+		{name: "year of string", fn: yearFunction, v: "2020"},
+		{name: "abs of string", fn: absFunction, v: "-3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := applyScalarFunction(tt.fn, reflect.ValueOf(tt.v))
+			if ok != tt.wantOk {
+				t.Fatalf("applyScalarFunction() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if ok && got.Interface() != tt.want {
+				t.Errorf("applyScalarFunction() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package jsonFilter
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// Map key type and composed key part of a map key converted at compile time
+type mapKeyPart struct {
+	t reflect.Type
+	p string
+}
+
+// Check if the composed key parts can be converted into keys of the map key type. The keys of the other types, like
+// float or interface, are compared on their string representation
+func isConvertibleMapKey(t reflect.Type) bool {
+	if reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+// Convert the composed key part into a key of the map key type, and keep it for the filter application.
+// Return an error if the part can't be converted
+func (f *Filter) compileMapKey(p string, t reflect.Type) error {
+	if !isConvertibleMapKey(t) {
+		return nil
+	}
+	mk, err := parseMapKey(p, t)
+	if err != nil {
+		return err
+	}
+	if f.mapKeys == nil {
+		f.mapKeys = make(map[mapKeyPart]reflect.Value)
+	}
+	f.mapKeys[mapKeyPart{t: t, p: p}] = mk
+	return nil
+}
+
+// Convert the composed key part into a key of the map key type. The types implementing encoding.TextUnmarshaler are
+// converted with their UnmarshalText method, else the strings, the integers and the bools are parsed.
+// Return an error if the part can't be converted
+func parseMapKey(p string, t reflect.Type) (reflect.Value, error) {
+	if reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		k := reflect.New(t)
+		if err := k.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(p)); err != nil {
+			return reflect.Value{}, errors.New(fmt.Sprintf("%s isn't a valid %s map key: %s", p, t, err))
+		}
+		return k.Elem(), nil
+	}
+
+	k := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.String:
+		k.SetString(p)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(p, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, errors.New(fmt.Sprintf("%s isn't a valid %s map key", p, t))
+		}
+		k.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(p, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, errors.New(fmt.Sprintf("%s isn't a valid %s map key", p, t))
+		}
+		k.SetUint(u)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(p)
+		if err != nil {
+			return reflect.Value{}, errors.New(fmt.Sprintf("%s isn't a valid %s map key", p, t))
+		}
+		k.SetBool(b)
+	default:
+		return reflect.Value{}, errors.New(fmt.Sprintf("the map key type %s isn't supported", t))
+	}
+	return k, nil
+}
+
+// Get the entry of the map for the composed key part, the part being converted in the map key type at compile time.
+// The keys of the types which aren't converted are compared on their string representation. With the case insensitive
+// keys, the string key matching the part case insensitively is used if there is no exact match.
+// Return an invalid value if no entry matches, or if several entries match case insensitively
+func (f *Filter) findMapEntry(m reflect.Value, p string) reflect.Value {
+	kt := m.Type().Key()
+	if !isConvertibleMapKey(kt) {
+		for _, k := range m.MapKeys() {
+			if fmt.Sprint(k) == p {
+				return m.MapIndex(k)
+			}
+		}
+		return reflect.Value{}
+	}
+
+	mk, ok := f.mapKeys[mapKeyPart{t: kt, p: p}]
+	if !ok {
+		// The key hasn't been converted at compile time by this filter
+		var err error
+		if mk, err = parseMapKey(p, kt); err != nil {
+			return reflect.Value{}
+		}
+	}
+	res := m.MapIndex(mk)
+	if res.IsValid() || !f.options.CaseInsensitiveKeys || m.Type().Key().Kind() != reflect.String {
+		return res
+	}
+
+	for _, k := range m.MapKeys() {
+		if !strings.EqualFold(k.String(), p) {
+			continue
+		}
+		// The entry is ambiguous
+		if res.IsValid() {
+			return reflect.Value{}
+		}
+		res = m.MapIndex(k)
+	}
+	return res
+}
@@ -0,0 +1,146 @@
+package jsonFilter
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Map key type implementing encoding.TextUnmarshaler
+type upperKey string
+
+func (k *upperKey) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return errors.New("empty key")
+	}
+	*k = upperKey(strings.ToUpper(string(text)))
+	return nil
+}
+
+type stringKey string
+
+func Test_parseMapKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		p       string
+		t       reflect.Type
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "string", p: "entry1", t: reflect.TypeOf(""), want: "entry1"},
+		{name: "custom string", p: "entry1", t: reflect.TypeOf(stringKey("")), want: stringKey("entry1")},
+		{name: "int", p: "-12", t: reflect.TypeOf(0), want: -12},
+		{name: "int8 overflow", p: "128", t: reflect.TypeOf(int8(0)), wantErr: true},
+		{name: "not an int", p: "entry1", t: reflect.TypeOf(0), wantErr: true},
+		{name: "uint", p: "12", t: reflect.TypeOf(uint16(0)), want: uint16(12)},
+		{name: "negative uint", p: "-12", t: reflect.TypeOf(uint(0)), wantErr: true},
+		{name: "bool", p: "true", t: reflect.TypeOf(false), want: true},
+		{name: "not a bool", p: "yes", t: reflect.TypeOf(false), wantErr: true},
+		{name: "text unmarshaler", p: "entry1", t: reflect.TypeOf(upperKey("")), want: upperKey("ENTRY1")},
+		{name: "text unmarshaler error", p: "", t: reflect.TypeOf(upperKey("")), wantErr: true},
+		{name: "not supported", p: "1.5", t: reflect.TypeOf(1.5), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseMapKey(tt.p, tt.t)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseMapKey() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.Interface() != tt.want {
+				t.Errorf("parseMapKey() = %v, want %v", got, fmt.Sprint(tt.want))
+			}
+		})
+	}
+}
+
+func TestFilter_mapKeyTypes(t *testing.T) {
+	type mapStruct struct {
+		MapInt       map[int]string         `json:"mapInt"`
+		MapFloat     map[float64]string     `json:"mapFloat"`
+		MapInterface map[interface{}]string `json:"mapInterface"`
+	}
+	entries := []mapStruct{
+		{
+			MapInt:       map[int]string{12: "v1"},
+			MapFloat:     map[float64]string{1.5: "v1"},
+			MapInterface: map[interface{}]string{"x": "v1", 2: "v2"},
+		},
+		{
+			MapInt:       map[int]string{12: "v2"},
+			MapFloat:     map[float64]string{2: "v2"},
+			MapInterface: map[interface{}]string{"x": "v2"},
+		},
+	}
+	tests := []struct {
+		name    string
+		filter  string
+		want    []mapStruct
+		wantErr bool
+	}{
+		{
+			name:   "converted key",
+			filter: "mapInt.12=v1",
+			want:   entries[:1],
+		},
+		{
+			name:    "not convertible key",
+			filter:  "mapInt.x=v1",
+			wantErr: true,
+		},
+		{
+			name:   "float key by string representation",
+			filter: "mapFloat.2=v2",
+			want:   entries[1:],
+		},
+		{
+			name:   "interface string key by string representation",
+			filter: "mapInterface.x=v2",
+			want:   entries[1:],
+		},
+		{
+			name:   "interface int key by string representation",
+			filter: "mapInterface.2=v2",
+			want:   entries[:1],
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{}
+			if err := f.Init(tt.filter, mapStruct{}); (err != nil) != tt.wantErr {
+				t.Fatalf("Init() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got, err := f.ApplyFilter(entries)
+			if err != nil {
+				t.Fatalf("ApplyFilter() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ApplyFilter() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter_compileMapKey(t *testing.T) {
+	f := &Filter{}
+	if err := f.Init("mapRootInt.12=val1:mapRootString.entry1=val1", testStruct{}); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	want := map[mapKeyPart]reflect.Value{
+		{t: reflect.TypeOf(0), p: "12"}:      reflect.ValueOf(12),
+		{t: reflect.TypeOf(""), p: "entry1"}: reflect.ValueOf("entry1"),
+	}
+	if len(f.mapKeys) != len(want) {
+		t.Fatalf("compileMapKey() keys = %v, want %v", f.mapKeys, want)
+	}
+	for mkp, wk := range want {
+		if k, ok := f.mapKeys[mkp]; !ok || k.Interface() != wk.Interface() {
+			t.Errorf("compileMapKey() key %v = %v, want %v", mkp, k, wk)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package jsonFilter
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+/*
+Function of a custom operator. The entry value is a leaf value of the filter key, like an element of an array. The
+filter values are the values of the filter, split by the value separator.
+
+Return true if the entry value matches the filter values. The quantifiers are applied on the leaf values of the key,
+like with the built-in operators: by default, at least one entry value must match.
+*/
+type OperatorFunc func(entryValue reflect.Value, filterValues []string) bool
+
+/*
+Register a custom operator on the filter, like a domain specific comparison. The operator token separates the key from
+the values in the filter, like the built-in operators. When several operators split a filter, the longest one is kept.
+
+Register the custom operators after setting the options and before the initialization:
+
+	filter := jsonFilter.Filter{}
+	err := filter.RegisterOperator("~region=", func(ev reflect.Value, vs []string) bool {
+		return isInRegions(ev.String(), vs)
+	})
+
+An error is returned if the token is empty, if it's already a built-in or a custom operator, or if the function is nil.
+*/
+func (f *Filter) RegisterOperator(op string, fn OperatorFunc) error {
+	if f.options == nil {
+		f.options = defaultOption
+	}
+	if op == "" {
+		return errors.New("The custom operator can't be empty")
+	}
+	if fn == nil {
+		return errors.New(fmt.Sprintf("The function of the custom operator %s can't be nil", op))
+	}
+	for _, o := range f.operators() {
+		if o == op {
+			return errors.New(fmt.Sprintf("The custom operator %s is already an operator", op))
+		}
+	}
+
+	if f.customOperators == nil {
+		f.customOperators = make(map[string]OperatorFunc)
+	}
+	f.customOperators[op] = fn
+	return nil
+}
+
+// Get the tokens of the custom operators, sorted to keep the operator selection deterministic
+func (f *Filter) customOperatorTokens() []string {
+	ops := make([]string, 0, len(f.customOperators))
+	for op := range f.customOperators {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+	return ops
+}
@@ -0,0 +1,126 @@
+package jsonFilter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Match the entry values which have one of the filter values as family, like ABC in ABC-123
+func skuFamily(ev reflect.Value, vs []string) bool {
+	for _, v := range vs {
+		if strings.HasPrefix(ev.String(), v+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+func TestFilter_RegisterOperator(t *testing.T) {
+	tests := []struct {
+		name    string
+		op      string
+		fn      OperatorFunc
+		wantErr bool
+	}{
+		{
+			name: "custom operator",
+			op:   "~family=",
+			fn:   skuFamily,
+		},
+		{
+			name:    "empty operator",
+			op:      "",
+			fn:      skuFamily,
+			wantErr: true,
+		},
+		{
+			name:    "nil function",
+			op:      "~family=",
+			wantErr: true,
+		},
+		{
+			name:    "built-in operator",
+			op:      defaultOption.NotEqualKeyValueSeparator,
+			fn:      skuFamily,
+			wantErr: true,
+		},
+		{
+			name:    "built-in operator with modifier",
+			op:      defaultOption.EqualKeyValueSeparator + defaultOption.InsensitiveModifier,
+			fn:      skuFamily,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{}
+			if err := f.RegisterOperator(tt.op, tt.fn); (err != nil) != tt.wantErr {
+				t.Errorf("RegisterOperator() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFilter_RegisterOperator_twice(t *testing.T) {
+	f := &Filter{}
+	if err := f.RegisterOperator("~family=", skuFamily); err != nil {
+		t.Fatalf("RegisterOperator() error = %v", err)
+	}
+	if err := f.RegisterOperator("~family=", skuFamily); err == nil {
+		t.Errorf("RegisterOperator() error = nil, want already registered error")
+	}
+}
+
+func TestFilter_customOperator(t *testing.T) {
+	entries := []testStruct{
+		{RootString: "value1", RootArraySimple: []string{"ABC-123", "XYZ-1"}},
+		{RootString: "value2", RootArraySimple: []string{"DEF-456"}},
+		{RootString: "value3", RootArraySimple: []string{"ABCD-1"}},
+	}
+	tests := []struct {
+		name    string
+		filter  string
+		want    []testStruct
+		wantErr bool
+	}{
+		{
+			name:   "custom operator with several values",
+			filter: "arrayRootSimple~family=ABC,DEF",
+			want:   entries[:2],
+		},
+		{
+			name:   "custom operator with quantifier",
+			filter: "all(arrayRootSimple)~family=ABC",
+			want:   []testStruct{},
+		},
+		{
+			name:   "custom operator and built-in operator",
+			filter: "arrayRootSimple~family=ABC,DEF:stringRoot!=value1",
+			want:   entries[1:2],
+		},
+		{
+			name:   "custom operator values aren't references",
+			filter: "arrayRootSimple~family=$ABC",
+			want:   []testStruct{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{}
+			if err := f.RegisterOperator("~family=", skuFamily); err != nil {
+				t.Fatalf("RegisterOperator() error = %v", err)
+			}
+			if err := f.Init(tt.filter, testStruct{}); (err != nil) != tt.wantErr {
+				t.Fatalf("Init() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			got, err := f.ApplyFilter(entries)
+			if err != nil {
+				t.Fatalf("ApplyFilter() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ApplyFilter() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
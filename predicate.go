@@ -0,0 +1,146 @@
+package jsonFilter
+
+import (
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// Predicates applicable on a key, without values, like exists(key)
+const (
+	// The key is rendered in JSON, even with a null value
+	existsPredicate = "exists"
+	// The key isn't rendered in JSON: not existing map entry, nil pointer on the path, or empty value with omitempty
+	missingPredicate = "missing"
+	// The key is rendered in JSON with a null value: nil pointer, array, map or interface
+	nullPredicate = "null"
+	// The key is rendered in JSON with a value which isn't null
+	notNullPredicate = "notnull"
+	// The string, array or map of the key has no element. Missing and nil values are empty
+	emptyPredicate = "empty"
+	// The string, array or map of the key has at least one element
+	notEmptyPredicate = "notempty"
+)
+
+var predicates = []string{existsPredicate, missingPredicate, nullPredicate, notNullPredicate, emptyPredicate,
+	notEmptyPredicate}
+
+// Check if the operator is a predicate
+func isPredicate(op string) bool {
+	for _, p := range predicates {
+		if p == op {
+			return true
+		}
+	}
+	return false
+}
+
+// Get the predicate and the key of a predicate filter, like exists(key).
+// Return false if the filter isn't a predicate
+func getPredicateAndKey(filter string) (k string, p string, ok bool) {
+	for _, p := range predicates {
+		if strings.HasPrefix(filter, p+"(") && strings.HasSuffix(filter, ")") {
+			return filter[len(p)+1 : len(filter)-1], p, true
+		}
+	}
+	return "", "", false
+}
+
+// Check if the predicate of the filter matches the entry value. The predicates are evaluated on the values found at
+// the end of the composed key, as encoding/json would render them, or on their length for the empty predicates.
+// In case of several values (array of struct on the path), at least one must match, except for missing and empty
+// predicates where none must be respectively rendered and not empty.
+func (f *Filter) matchPredicate(kov kov, evs reflect.Value) bool {
+	rendered, null, notNull, notEmpty := false, false, false, false
+	for _, cv := range f.findContainerInComposedKey(kov.Key, evs) {
+		if containerLen(cv.value) > 0 {
+			notEmpty = true
+		}
+		if !cv.value.IsValid() || (cv.omitEmpty && isEmptyValue(cv.value)) {
+			continue
+		}
+		rendered = true
+		if isNullValue(cv.value) {
+			null = true
+		} else {
+			notNull = true
+		}
+	}
+
+	switch kov.Operator {
+	case existsPredicate:
+		return rendered
+	case missingPredicate:
+		return !rendered
+	case nullPredicate:
+		return null
+	case notNullPredicate:
+		return notNull
+	case emptyPredicate:
+		return !notEmpty
+	case notEmptyPredicate:
+		return notEmpty
+	}
+	return false
+}
+
+// Get the number of elements of the string, array or map container, without flattening it. The length of a string is
+// its number of characters (runes), like in JSON. The pointers and the interfaces are browsed, and 0 is returned if
+// they are nil.
+// Return -1 if the value isn't a container
+func containerLen(v reflect.Value) int {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return 0
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(v.String())
+	case reflect.Array, reflect.Slice, reflect.Map:
+		return v.Len()
+	}
+	return -1
+}
+
+// Check if the type is a container (string, array or map) which can be evaluated by the empty predicates.
+// The interfaces are accepted and evaluated only when the filter is applied
+func isContainerType(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Array, reflect.Slice, reflect.Map, reflect.Interface:
+		return true
+	}
+	return false
+}
+
+// Check if the value is rendered as null in JSON
+func isNullValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		return v.IsNil()
+	}
+	return false
+}
+
+// Check if the value is empty, and thus omitted in JSON with the omitempty option. Same rules as encoding/json
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
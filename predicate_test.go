@@ -0,0 +1,156 @@
+package jsonFilter
+
+import (
+	"reflect"
+	"testing"
+)
+
+type predicateStruct struct {
+	OmitString string            `json:"omitString,omitempty"`
+	String     string            `json:"string"`
+	OmitPtr    *SubStruct        `json:"omitPtr,omitempty"`
+	Ptr        *SubStruct        `json:"ptr"`
+	Slice      []string          `json:"slice"`
+	Map        map[string]string `json:"map"`
+	MapPtr     map[string]*int   `json:"mapPtr"`
+	Array      []SubStruct       `json:"array,omitempty"`
+	Tags       []string          `json:"tags"`
+	EmptyMap   map[string]string `json:"emptyMap"`
+}
+
+func Test_getPredicateAndKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter string
+		wantK  string
+		wantP  string
+		wantOk bool
+	}{
+		{
+			name:   "exists",
+			filter: "exists(key1.sub)",
+			wantK:  "key1.sub",
+			wantP:  existsPredicate,
+			wantOk: true,
+		},
+		{
+			name:   "notnull",
+			filter: "notnull(key1)",
+			wantK:  "key1",
+			wantP:  notNullPredicate,
+			wantOk: true,
+		},
+		{
+			name:   "unknown predicate",
+			filter: "unknown(key1)",
+			wantOk: false,
+		},
+		{
+			name:   "not closed",
+			filter: "null(key1",
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotK, gotP, gotOk := getPredicateAndKey(tt.filter)
+			if gotK != tt.wantK || gotP != tt.wantP || gotOk != tt.wantOk {
+				t.Errorf("getPredicateAndKey() = %v, %v, %v, want %v, %v, %v", gotK, gotP, gotOk, tt.wantK, tt.wantP, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestFilter_matchPredicate(t *testing.T) {
+	one := 1
+	entry := reflect.ValueOf(predicateStruct{
+		Ptr:      &SubStruct{},
+		Map:      map[string]string{"entry": ""},
+		MapPtr:   map[string]*int{"nil": nil, "one": &one},
+		Array:    []SubStruct{{SubString: "val1"}, {}},
+		Tags:     []string{"tag1"},
+		EmptyMap: map[string]string{},
+	})
+	tests := []struct {
+		name      string
+		key       string
+		wantTrues []string
+	}{
+		{
+			name:      "empty value with omitempty",
+			key:       "OmitString",
+			wantTrues: []string{missingPredicate, emptyPredicate},
+		},
+		{
+			name:      "empty value without omitempty",
+			key:       "String",
+			wantTrues: []string{existsPredicate, notNullPredicate, emptyPredicate},
+		},
+		{
+			name:      "nil pointer with omitempty",
+			key:       "OmitPtr",
+			wantTrues: []string{missingPredicate, emptyPredicate},
+		},
+		{
+			name:      "nil pointer on the path",
+			key:       "OmitPtr.SubString",
+			wantTrues: []string{missingPredicate, emptyPredicate},
+		},
+		{
+			name:      "pointer without omitempty",
+			key:       "Ptr",
+			wantTrues: []string{existsPredicate, notNullPredicate, emptyPredicate},
+		},
+		{
+			name:      "nil slice without omitempty",
+			key:       "Slice",
+			wantTrues: []string{existsPredicate, nullPredicate, emptyPredicate},
+		},
+		{
+			name:      "map entry",
+			key:       "Map.entry",
+			wantTrues: []string{existsPredicate, notNullPredicate, emptyPredicate},
+		},
+		{
+			name:      "not existing map entry",
+			key:       "Map.unknown",
+			wantTrues: []string{missingPredicate, emptyPredicate},
+		},
+		{
+			name:      "nil map entry",
+			key:       "MapPtr.nil",
+			wantTrues: []string{existsPredicate, nullPredicate, emptyPredicate},
+		},
+		{
+			name:      "not empty slice",
+			key:       "Tags",
+			wantTrues: []string{existsPredicate, notNullPredicate, notEmptyPredicate},
+		},
+		{
+			name:      "empty map",
+			key:       "EmptyMap",
+			wantTrues: []string{existsPredicate, notNullPredicate, emptyPredicate},
+		},
+		{
+			name:      "array of struct with one empty value",
+			key:       "Array.SubString",
+			wantTrues: []string{existsPredicate, notNullPredicate, notEmptyPredicate},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{
+				options: defaultOption,
+			}
+			for _, p := range predicates {
+				want := false
+				for _, wp := range tt.wantTrues {
+					want = want || wp == p
+				}
+				if got := f.matchPredicate(kov{Key: tt.key, Operator: p}, entry); got != want {
+					t.Errorf("matchPredicate() %s = %v, want %v", p, got, want)
+				}
+			}
+		})
+	}
+}
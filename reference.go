@@ -0,0 +1,156 @@
+package jsonFilter
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Check if the filter value is a reference to another key of the entry, like $key. A value starting with the reference
+// prefix twice, like $$key, is an escaped literal value.
+// Without reference prefix in the options, there is no reference
+func (f *Filter) isReference(v string) bool {
+	p := f.options.FieldReferencePrefix
+	return p != "" && strings.HasPrefix(v, p) && len(v) > len(p) && !strings.HasPrefix(v, p+p)
+}
+
+// Get the literal value of a filter value which isn't a reference: the escaped literal values, like $$promo, lose their
+// first reference prefix, like $promo. The other values are returned as is
+func unescapeReference(v string, prefix string) string {
+	if prefix != "" && strings.HasPrefix(v, prefix+prefix) {
+		return v[len(prefix):]
+	}
+	return v
+}
+
+// Find the struct field names in relation with the keys referenced in the filter values, like $key, and check that
+// their values can be compared with the values of the filter key, according with the operator. The values of the
+// custom operators aren't references.
+// Return the filter values with the referenced keys replaced by the struct field names
+func (f *Filter) compileReferences(kov kov, kt reflect.Type, t reflect.Type) ([]string, error) {
+	// The values of the custom operators are provided as is to their function
+	if _, ok := f.customOperators[kov.Operator]; ok {
+		return kov.Values, nil
+	}
+
+	var vs []string
+	for _, v := range kov.Values {
+		if !f.isReference(v) {
+			vs = append(vs, v)
+			continue
+		}
+
+		rk, rt, err := f.compileKey(strings.TrimPrefix(v, f.options.FieldReferencePrefix), t)
+		if err != nil {
+			return nil, err
+		}
+		// The operator must be allowed on the struct fields of the referenced key too
+		rkov := kov
+		rkov.Key, rkov.Values = rk, []string{v}
+		if err = f.checkOperator(rkov, t); err != nil {
+			return nil, err
+		}
+		if !f.isComparableWith(kov.Operator, leafType(kt), leafType(rt)) {
+			return nil, errors.New(fmt.Sprintf("The Filter key %s can't be compared with the referenced key %s with the operator %s", kov.Key, rk, kov.Operator))
+		}
+		vs = append(vs, f.options.FieldReferencePrefix+rk)
+	}
+	return vs, nil
+}
+
+// Check if the values of the key type can be compared with the values of the referenced key type with the operator.
+// The interface types can be compared with all the types, their values are checked when the filter is applied
+func (f *Filter) isComparableWith(op string, kt reflect.Type, rt reflect.Type) bool {
+	if kt.Kind() == reflect.Interface || rt.Kind() == reflect.Interface {
+		return true
+	}
+
+	// The time and the duration are only comparable with themselves, the numeric values are comparable together
+	sameType := false
+	switch {
+	case kt == timeType || rt == timeType, kt == durationType || rt == durationType:
+		sameType = kt == rt
+	case isNumericKind(kt.Kind()):
+		sameType = isNumericKind(rt.Kind())
+	default:
+		sameType = kt.Kind() == rt.Kind()
+	}
+
+	switch op {
+	case f.options.EqualKeyValueSeparator, f.options.NotEqualKeyValueSeparator:
+		return sameType
+	case f.options.GreaterThanKeyValueSeparator, f.options.LowerThanKeyValueSeparator:
+		return sameType && (kt == timeType || isNumericKind(kt.Kind()) || kt.Kind() == reflect.String)
+	case f.options.ContainsKeyValueSeparator, f.options.StartsWithKeyValueSeparator, f.options.EndsWithKeyValueSeparator:
+		return sameType && kt.Kind() == reflect.String
+	}
+	return false
+}
+
+// Find the values of the keys referenced in the filter values, like $key, in the entry.
+// Return nil if there is no reference in the filter values
+func (f *Filter) findReferenceValues(kov kov, evs reflect.Value) map[string][]reflect.Value {
+	var refs map[string][]reflect.Value
+	for _, v := range kov.Values {
+		if !f.isReference(v) {
+			continue
+		}
+		if refs == nil {
+			refs = make(map[string][]reflect.Value)
+		}
+		refs[v] = f.findValueInKey(strings.TrimPrefix(v, f.options.FieldReferencePrefix), evs)
+	}
+	return refs
+}
+
+// Replace the references in the filter values by the string values of the referenced keys, and unescape the literal
+// values. The values which aren't string are ignored
+func (c *comparator) expandReferences(vs []string, refs map[string][]reflect.Value) []string {
+	var r []string
+	for _, v := range vs {
+		rvs, ok := refs[v]
+		if !ok {
+			r = append(r, unescapeReference(v, c.referencePrefix))
+			continue
+		}
+		for _, rv := range rvs {
+			if rv = concreteValue(rv); rv.IsValid() && rv.Kind() == reflect.String {
+				r = append(r, rv.String())
+			}
+		}
+	}
+	return r
+}
+
+// Check if the entry value matches the filter value or, if the filter value is a reference, if it's equal to at least
+// one value of the referenced key
+func (c *comparator) matchOrReference(ev reflect.Value, v string, refs map[string][]reflect.Value) bool {
+	rvs, ok := refs[v]
+	if !ok {
+		return c.match(ev, unescapeReference(v, c.referencePrefix))
+	}
+	for _, rv := range rvs {
+		if c.equalValues(ev, rv) {
+			return true
+		}
+	}
+	return false
+}
+
+// Compare the entry value with the filter value or, if the filter value is a reference, with the values of the
+// referenced key.
+// Return true if the comparison result is accepted, for at least one value of the referenced key
+func (c *comparator) compareOrReference(ev reflect.Value, v string, refs map[string][]reflect.Value, accept func(r int) bool) bool {
+	rvs, ok := refs[v]
+	if !ok {
+		r, ok := c.compare(ev, unescapeReference(v, c.referencePrefix))
+		return ok && accept(r)
+	}
+	for _, rv := range rvs {
+		if r, ok := c.compareValues(ev, rv); ok && accept(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+package jsonFilter
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFilter_compileReferences(t *testing.T) {
+	tests := []struct {
+		name    string
+		kov     kov
+		kt      reflect.Type
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "no reference",
+			kov:  kov{Key: "RootInt", Operator: "=", Values: []string{"1", "2"}},
+			kt:   reflect.TypeOf(0),
+			want: []string{"1", "2"},
+		},
+		{
+			name: "numeric references",
+			kov:  kov{Key: "RootInt", Operator: ">", Values: []string{"$floatRoot"}},
+			kt:   reflect.TypeOf(0),
+			want: []string{"$RootFloat"},
+		},
+		{
+			name: "references mixed with values",
+			kov:  kov{Key: "RootString", Operator: "=", Values: []string{"val1", "$arrayRoot.stringSub", "$"}},
+			kt:   reflect.TypeOf(""),
+			want: []string{"val1", "$RootArray.SubString", "$"},
+		},
+		{
+			name: "escaped literal values",
+			kov:  kov{Key: "RootString", Operator: "=", Values: []string{"$$promo", "$$"}},
+			kt:   reflect.TypeOf(""),
+			want: []string{"$$promo", "$$"},
+		},
+		{
+			name: "reference to a function",
+			kov:  kov{Key: "RootInt", Operator: "<", Values: []string{"$len(arrayRootSimple)"}},
+			kt:   reflect.TypeOf(0),
+			want: []string{"$len(RootArraySimple)"},
+		},
+		{
+			name: "time reference",
+			kov:  kov{Key: "RootTime", Operator: "<", Values: []string{"$ptrTimeRoot"}},
+			kt:   reflect.TypeOf(time.Time{}),
+			want: []string{"$RootPtrTime"},
+		},
+		{
+			name:    "unknown reference",
+			kov:     kov{Key: "RootInt", Operator: "=", Values: []string{"$unknown"}},
+			kt:      reflect.TypeOf(0),
+			wantErr: true,
+		},
+		{
+			name:    "time and numeric",
+			kov:     kov{Key: "RootTime", Operator: ">", Values: []string{"$intRoot"}},
+			kt:      reflect.TypeOf(time.Time{}),
+			wantErr: true,
+		},
+		{
+			name:    "bool not ordered",
+			kov:     kov{Key: "RootBool", Operator: ">", Values: []string{"$boolRoot"}},
+			kt:      reflect.TypeOf(false),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{
+				options: defaultOption,
+			}
+			got, err := f.compileReferences(tt.kov, tt.kt, reflect.TypeOf(testStruct{}))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("compileReferences() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("compileReferences() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter_escapedReferences(t *testing.T) {
+	entries := []testStruct{
+		{RootString: "$promo"},
+		{RootString: "promo"},
+		{RootString: "$"},
+	}
+	tests := []struct {
+		name    string
+		filter  string
+		want    []testStruct
+		wantErr bool
+	}{
+		{
+			name:   "escaped literal value",
+			filter: "stringRoot=$$promo",
+			want:   entries[:1],
+		},
+		{
+			name:   "escaped literal value mixed with values",
+			filter: "stringRoot!=$$promo,$$",
+			want:   entries[1:2],
+		},
+		{
+			name:   "escaped literal prefix",
+			filter: "stringRoot^=$$",
+			want:   []testStruct{entries[0], entries[2]},
+		},
+		{
+			name:   "escaped literal value compared",
+			filter: "stringRoot<$$p",
+			want:   entries[2:],
+		},
+		{
+			name:    "unknown reference",
+			filter:  "stringRoot=$promo",
+			wantErr: true,
+		},
+		{
+			name:    "escaped literal value not numeric",
+			filter:  "intRoot=$$5",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{}
+			err := f.Init(tt.filter, testStruct{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Init() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got, err := f.ApplyFilter(entries)
+			if err != nil {
+				t.Fatalf("ApplyFilter() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ApplyFilter() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter_isComparableWith(t *testing.T) {
+	tests := []struct {
+		name string
+		op   string
+		kt   reflect.Type
+		rt   reflect.Type
+		want bool
+	}{
+		{name: "int and float", op: ">", kt: reflect.TypeOf(0), rt: reflect.TypeOf(1.5), want: true},
+		{name: "string and string", op: "^=", kt: reflect.TypeOf(""), rt: reflect.TypeOf(""), want: true},
+		{name: "duration and duration", op: "<", kt: durationType, rt: durationType, want: true},
+		{name: "duration and int", op: "=", kt: durationType, rt: reflect.TypeOf(0), want: false},
+		{name: "bool equality", op: "!=", kt: reflect.TypeOf(false), rt: reflect.TypeOf(false), want: true},
+		{name: "bool order", op: "<", kt: reflect.TypeOf(false), rt: reflect.TypeOf(false), want: false},
+		{name: "int and string", op: "=", kt: reflect.TypeOf(0), rt: reflect.TypeOf(""), want: false},
+		{name: "contains on int", op: "*=", kt: reflect.TypeOf(0), rt: reflect.TypeOf(0), want: false},
+		{name: "interface", op: ">", kt: reflect.TypeOf((*interface{})(nil)).Elem(), rt: timeType, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{
+				options: defaultOption,
+			}
+			if got := f.isComparableWith(tt.op, tt.kt, tt.rt); got != tt.want {
+				t.Errorf("isComparableWith() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
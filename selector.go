@@ -0,0 +1,151 @@
+package jsonFilter
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Selector of the elements of an array in a composed key part, like items[0], items[-1] or items[0:3]
+type selector struct {
+	// Index of the element, or start of the range. Negative values are counted from the end of the array
+	start int
+	// End (excluded) of the range. Negative values are counted from the end of the array
+	end int
+	// The selector is a range of elements, like [0:3], else it's an index, like [0]
+	isRange bool
+	// The bounds of the range are defined. If not, the range starts at the beginning or stops at the end of the array
+	hasStart bool
+	hasEnd   bool
+}
+
+// Get the name and the selectors of a composed key part, like items[0] or matrix[0][1:].
+// Return an error if the selectors aren't valid
+func parseKeyPart(p string) (name string, sels []selector, err error) {
+	i := strings.Index(p, "[")
+	if i < 0 {
+		return p, nil, nil
+	}
+	name = p[:i]
+
+	// Parse all the selectors, one after the other
+	for r := p[i:]; r != ""; {
+		e := strings.Index(r, "]")
+		if !strings.HasPrefix(r, "[") || e < 0 {
+			return "", nil, errors.New(fmt.Sprintf("The Filter key part %s has invalid array selectors", p))
+		}
+		s, err := parseSelector(r[1:e])
+		if err != nil {
+			return "", nil, errors.New(fmt.Sprintf("The Filter key part %s has an invalid array selector: %s", p, err))
+		}
+		sels = append(sels, s)
+		r = r[e+1:]
+	}
+	return
+}
+
+// Parse an index, like 0 or -1, or a range, like 0:3, 1: or :-1
+func parseSelector(v string) (s selector, err error) {
+	b := strings.Split(v, ":")
+	switch len(b) {
+	case 1:
+		s.hasStart = true
+		s.start, err = strconv.Atoi(v)
+	case 2:
+		s.isRange = true
+		if b[0] != "" {
+			s.hasStart = true
+			if s.start, err = strconv.Atoi(b[0]); err != nil {
+				return
+			}
+		}
+		if b[1] != "" {
+			s.hasEnd = true
+			s.end, err = strconv.Atoi(b[1])
+		}
+	default:
+		err = errors.New(fmt.Sprintf("%s isn't an index or a range", v))
+	}
+	return
+}
+
+// Get the type of the value selected in an array. The indexes select an element of the array, the ranges keep the array
+// Return an error if the type isn't an array
+func compileSelectors(t reflect.Type, sels []selector) (reflect.Type, error) {
+	for _, s := range sels {
+		//In case of ptr
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Slice {
+			return nil, errors.New(fmt.Sprintf("the type %s isn't an array", t))
+		}
+		if !s.isRange {
+			t = t.Elem()
+		}
+	}
+	return t, nil
+}
+
+// Apply the selectors on the value, one after the other. The indexes select an element of the array, the ranges
+// select a sub array.
+// Return false if a pointer is nil or if an index is out of the array. The ranges are truncated to the array length
+func applySelectors(v reflect.Value, sels []selector) (reflect.Value, bool) {
+	for _, s := range sels {
+		//In case of pointer
+		if v.Kind() == reflect.Ptr {
+			//If the pointer lead to nil value
+			if v.IsNil() {
+				return v, false
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Slice {
+			return v, false
+		}
+
+		l := v.Len()
+		if !s.isRange {
+			i := absoluteIndex(s.start, l)
+			if i < 0 || i >= l {
+				return v, false
+			}
+			v = v.Index(i)
+			continue
+		}
+
+		lo, hi := 0, l
+		if s.hasStart {
+			lo = truncate(absoluteIndex(s.start, l), l)
+		}
+		if s.hasEnd {
+			hi = truncate(absoluteIndex(s.end, l), l)
+		}
+		if hi < lo {
+			hi = lo
+		}
+		v = v.Slice(lo, hi)
+	}
+	return v, true
+}
+
+// Get the index from the beginning of the array, the negative indexes are counted from the end
+func absoluteIndex(i int, l int) int {
+	if i < 0 {
+		return i + l
+	}
+	return i
+}
+
+// Truncate the index between 0 and the array length
+func truncate(i int, l int) int {
+	if i < 0 {
+		return 0
+	}
+	if i > l {
+		return l
+	}
+	return i
+}
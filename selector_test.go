@@ -0,0 +1,173 @@
+package jsonFilter
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func Test_parseKeyPart(t *testing.T) {
+	tests := []struct {
+		name     string
+		p        string
+		wantName string
+		wantSels []selector
+		wantErr  bool
+	}{
+		{
+			name:     "no selector",
+			p:        "items",
+			wantName: "items",
+		},
+		{
+			name:     "index",
+			p:        "items[0]",
+			wantName: "items",
+			wantSels: []selector{{start: 0, hasStart: true}},
+		},
+		{
+			name:     "negative index",
+			p:        "items[-1]",
+			wantName: "items",
+			wantSels: []selector{{start: -1, hasStart: true}},
+		},
+		{
+			name:     "range",
+			p:        "items[0:3]",
+			wantName: "items",
+			wantSels: []selector{{start: 0, end: 3, isRange: true, hasStart: true, hasEnd: true}},
+		},
+		{
+			name:     "open ranges",
+			p:        "matrix[1:][:-1]",
+			wantName: "matrix",
+			wantSels: []selector{
+				{start: 1, isRange: true, hasStart: true},
+				{end: -1, isRange: true, hasEnd: true},
+			},
+		},
+		{
+			name:    "not an index",
+			p:       "items[first]",
+			wantErr: true,
+		},
+		{
+			name:    "too many bounds",
+			p:       "items[0:1:2]",
+			wantErr: true,
+		},
+		{
+			name:    "not closed",
+			p:       "items[0",
+			wantErr: true,
+		},
+		{
+			name:    "text after selector",
+			p:       "items[0]status",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotName, gotSels, err := parseKeyPart(tt.p)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseKeyPart() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotName != tt.wantName || !reflect.DeepEqual(gotSels, tt.wantSels) {
+				t.Errorf("parseKeyPart() = %v, %v, want %v, %v", gotName, gotSels, tt.wantName, tt.wantSels)
+			}
+		})
+	}
+}
+
+func Test_compileSelectors(t *testing.T) {
+	tests := []struct {
+		name    string
+		t       reflect.Type
+		p       string
+		want    reflect.Type
+		wantErr bool
+	}{
+		{
+			name: "index",
+			t:    reflect.TypeOf([]string{}),
+			p:    "k[0]",
+			want: reflect.TypeOf(""),
+		},
+		{
+			name: "range",
+			t:    reflect.TypeOf([]string{}),
+			p:    "k[0:1]",
+			want: reflect.TypeOf([]string{}),
+		},
+		{
+			name: "index of matrix",
+			t:    reflect.TypeOf(&[][]string{}),
+			p:    "k[0][1]",
+			want: reflect.TypeOf(""),
+		},
+		{
+			name:    "index of map",
+			t:       reflect.TypeOf(map[string]string{}),
+			p:       "k[0]",
+			wantErr: true,
+		},
+		{
+			name:    "too many indexes",
+			t:       reflect.TypeOf([]string{}),
+			p:       "k[0][1]",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, sels, _ := parseKeyPart(tt.p)
+			got, err := compileSelectors(tt.t, sels)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("compileSelectors() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("compileSelectors() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_applySelectors(t *testing.T) {
+	items := []string{"a", "b", "c", "d"}
+	tests := []struct {
+		name   string
+		v      interface{}
+		p      string
+		want   string
+		wantOk bool
+	}{
+		{name: "index", v: items, p: "k[1]", want: "b", wantOk: true},
+		{name: "negative index", v: items, p: "k[-1]", want: "d", wantOk: true},
+		{name: "index out of the array", v: items, p: "k[4]"},
+		{name: "negative index out of the array", v: items, p: "k[-5]"},
+		{name: "range", v: items, p: "k[0:3]", want: "[a b c]", wantOk: true},
+		{name: "range from the end", v: items, p: "k[-2:]", want: "[c d]", wantOk: true},
+		{name: "range to the end excluded", v: items, p: "k[:-3]", want: "[a]", wantOk: true},
+		{name: "range truncated", v: items, p: "k[2:10]", want: "[c d]", wantOk: true},
+		{name: "empty range", v: items, p: "k[3:1]", want: "[]", wantOk: true},
+		{name: "matrix", v: [][]string{{"a", "b"}, {"c"}}, p: "k[0][-1]", want: "b", wantOk: true},
+		{name: "pointer", v: &items, p: "k[0]", want: "a", wantOk: true},
+		{name: "nil pointer", v: (*[]string)(nil), p: "k[0]"},
+		{name: "nil array", v: []string(nil), p: "k[0:1]", want: "[]", wantOk: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, sels, _ := parseKeyPart(tt.p)
+			got, ok := applySelectors(reflect.ValueOf(tt.v), sels)
+			if ok != tt.wantOk {
+				t.Fatalf("applySelectors() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if ok && fmt.Sprint(got) != tt.want {
+				t.Errorf("applySelectors() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
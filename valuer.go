@@ -0,0 +1,148 @@
+package jsonFilter
+
+import (
+	"encoding"
+	"fmt"
+	"math/big"
+	"reflect"
+)
+
+/*
+Interface of the types compared by a canonical value, like a decimal, an enum or an identifier. The types implementing
+it are leaves of the filter keys, whatever their kind: a struct isn't browsed deeper.
+
+FilterValue returns the canonical value, a string, a bool, a numeric, a time.Time or a time.Duration value, compared to
+the filter values like the struct fields of this type. A nil value doesn't match, like a nil pointer.
+
+	func (d Decimal) FilterValue() interface{} {
+		return d.Float64()
+	}
+
+The types implementing fmt.Stringer or encoding.TextMarshaler are also compared by their string value:
+  - The string and the other not numeric kinds, like a string enum, are leaves compared by their string value
+  - The numeric kinds, like an int enum, are compared numerically, and by their string value with the equality
+    operators if the filter value isn't numeric, like level=high
+  - The struct kinds without exported field, like a decimal, are leaves compared by their string value, numerically if
+    it's a number. The struct kinds with exported fields are browsed
+  - The slice and the map kinds are browsed, time.Time and time.Duration keep their own comparison
+
+FilterValuer is used first, then fmt.Stringer, then encoding.TextMarshaler.
+*/
+type FilterValuer interface {
+	FilterValue() interface{}
+}
+
+var (
+	filterValuerType  = reflect.TypeOf((*FilterValuer)(nil)).Elem()
+	stringerType      = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	interfaceType     = reflect.TypeOf((*interface{})(nil)).Elem()
+)
+
+// Check if the type, or the pointer to the type, implements the interface. The interface types are checked on their
+// concrete values, the pointer types on their pointed values
+func implements(t reflect.Type, it reflect.Type) bool {
+	if t.Kind() == reflect.Interface || t.Kind() == reflect.Ptr {
+		return false
+	}
+	return t.Implements(it) || reflect.PtrTo(t).Implements(it)
+}
+
+// Get the type of the canonical values of the type: interface for the FilterValuer types and the opaque struct types
+// with a string value, the concrete values being checked when the filter is applied, string for the other types with a
+// string value.
+// Return false if the type values aren't converted, like time.Time, time.Duration and the numeric types
+func canonicalType(t reflect.Type) (reflect.Type, bool) {
+	switch {
+	case t == timeType || t == durationType:
+		return t, false
+	case implements(t, filterValuerType):
+		return interfaceType, true
+	case !hasStringValue(t), isNumericKind(t.Kind()):
+		return t, false
+	case t.Kind() == reflect.Struct:
+		return interfaceType, true
+	}
+	return stringType, true
+}
+
+// Check if the type values can be compared by their string value, with fmt.Stringer or encoding.TextMarshaler. The
+// slice and the map types are browsed, like the struct types with exported fields reachable by the filter
+func hasStringValue(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Slice, reflect.Map:
+		return false
+	case reflect.Struct:
+		if hasFilterableField(t) {
+			return false
+		}
+	}
+	return implements(t, stringerType) || implements(t, textMarshalerType)
+}
+
+// Check if the struct type has an exported field which isn't hidden by the filter struct tag
+func hasFilterableField(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if ft, _ := parseFilterTag(sf); sf.PkgPath == "" && !ft.hidden {
+			return true
+		}
+	}
+	return false
+}
+
+// Get the canonical value of the value, with FilterValuer, fmt.Stringer or encoding.TextMarshaler. The string value of
+// the opaque struct types is converted to a number if it's numeric, like a decimal.
+// Return the value as is if its type isn't converted, and an invalid value if the canonical value is nil or can't be
+// computed
+func canonicalValue(v reflect.Value) reflect.Value {
+	if _, ok := canonicalType(v.Type()); !ok || !v.CanInterface() {
+		return v
+	}
+
+	if fv, ok := pointerTo(v).Interface().(FilterValuer); ok {
+		return reflect.ValueOf(fv.FilterValue())
+	}
+	s, ok := stringValue(v)
+	if !ok {
+		return reflect.Value{}
+	}
+	if v.Kind() == reflect.Struct {
+		if n, err := parseNumber(s); err == nil {
+			// The integers are kept exact, like the numeric values
+			if i, acc := n.Int64(); n.IsInt() && acc == big.Exact {
+				return reflect.ValueOf(i)
+			}
+			fl, _ := n.Float64()
+			return reflect.ValueOf(fl)
+		}
+	}
+	return reflect.ValueOf(s)
+}
+
+// Get the string value of the value, with fmt.Stringer or encoding.TextMarshaler.
+// Return false if the value has no string value or if it can't be computed
+func stringValue(v reflect.Value) (string, bool) {
+	if !v.CanInterface() || !hasStringValue(v.Type()) {
+		return "", false
+	}
+	switch sv := pointerTo(v).Interface().(type) {
+	case fmt.Stringer:
+		return sv.String(), true
+	case encoding.TextMarshaler:
+		b, err := sv.MarshalText()
+		return string(b), err == nil
+	}
+	return "", false
+}
+
+// Get a pointer to the value, to call the methods with pointer receiver. If the value isn't addressable, the pointer is
+// on a copy of the value
+func pointerTo(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v.Addr()
+	}
+	p := reflect.New(v.Type())
+	p.Elem().Set(v)
+	return p
+}
@@ -0,0 +1,443 @@
+package jsonFilter
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Amount in cents, compared by its value in units
+type testAmount struct {
+	cents int64
+}
+
+func (a testAmount) FilterValue() interface{} {
+	return float64(a.cents) / 100
+}
+
+// The canonical value has priority over the string value
+func (a testAmount) String() string {
+	return "amount"
+}
+
+// Numeric enum, compared numerically or by its name
+type testStatus int
+
+func (s testStatus) String() string {
+	return [...]string{"open", "closed"}[s]
+}
+
+// String enum compared by its name
+type testColor string
+
+func (c testColor) String() string {
+	return "color-" + string(c)
+}
+
+// Identifier compared by its text, with a pointer receiver
+type testID string
+
+func (id *testID) MarshalText() ([]byte, error) {
+	return []byte("id-" + string(*id)), nil
+}
+
+// Decimal without exported field, compared by its string value
+type testDecimal struct {
+	units int64
+	cents int64
+}
+
+func (d testDecimal) String() string {
+	return fmt.Sprintf("%d.%02d", d.units, d.cents)
+}
+
+// Opaque struct with a string value which isn't numeric
+type testToken struct {
+	v string
+}
+
+func (tk testToken) String() string {
+	return "token-" + tk.v
+}
+
+// Struct browsed whatever its string value
+type testAddress struct {
+	City string `json:"city"`
+}
+
+func (a testAddress) String() string {
+	return "address in " + a.City
+}
+
+// Optional value, without canonical value if not defined
+type testOptional struct {
+	v *int
+}
+
+func (o testOptional) FilterValue() interface{} {
+	if o.v == nil {
+		return nil
+	}
+	return *o.v
+}
+
+type testValuerStruct struct {
+	Amount    testAmount   `json:"amount"`
+	Amounts   []testAmount `json:"amounts"`
+	PtrAmount *testAmount  `json:"ptrAmount"`
+	Status    testStatus   `json:"status"`
+	Color     testColor    `json:"color"`
+	ID        testID       `json:"id"`
+	Address   testAddress  `json:"addr"`
+	Price     testDecimal  `json:"price"`
+	Optional  testOptional `json:"optional"`
+	Any       interface{}  `json:"any"`
+}
+
+func Test_canonicalType(t *testing.T) {
+	tests := []struct {
+		name   string
+		t      reflect.Type
+		want   reflect.Type
+		wantOk bool
+	}{
+		{
+			name:   "filter valuer",
+			t:      reflect.TypeOf(testAmount{}),
+			want:   interfaceType,
+			wantOk: true,
+		},
+		{
+			name:   "stringer",
+			t:      reflect.TypeOf(testColor("")),
+			want:   stringType,
+			wantOk: true,
+		},
+		{
+			name:   "text marshaler with pointer receiver",
+			t:      reflect.TypeOf(testID("")),
+			want:   stringType,
+			wantOk: true,
+		},
+		{
+			name: "numeric stringer",
+			t:    reflect.TypeOf(testStatus(0)),
+			want: reflect.TypeOf(testStatus(0)),
+		},
+		{
+			name: "struct stringer with exported field",
+			t:    reflect.TypeOf(testAddress{}),
+			want: reflect.TypeOf(testAddress{}),
+		},
+		{
+			name:   "struct stringer without exported field",
+			t:      reflect.TypeOf(testDecimal{}),
+			want:   interfaceType,
+			wantOk: true,
+		},
+		{
+			name: "time",
+			t:    timeType,
+			want: timeType,
+		},
+		{
+			name: "duration",
+			t:    durationType,
+			want: durationType,
+		},
+		{
+			name: "pointer",
+			t:    reflect.TypeOf(&testAmount{}),
+			want: reflect.TypeOf(&testAmount{}),
+		},
+		{
+			name: "interface",
+			t:    stringerType,
+			want: stringerType,
+		},
+		{
+			name: "struct",
+			t:    reflect.TypeOf(SubStruct{}),
+			want: reflect.TypeOf(SubStruct{}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotOk := canonicalType(tt.t)
+			if got != tt.want || gotOk != tt.wantOk {
+				t.Errorf("canonicalType() = %v, %v, want %v, %v", got, gotOk, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_canonicalValue(t *testing.T) {
+	one := 1
+	tests := []struct {
+		name      string
+		v         reflect.Value
+		want      interface{}
+		wantValid bool
+	}{
+		{
+			name:      "filter valuer",
+			v:         reflect.ValueOf(testAmount{cents: 150}),
+			want:      1.5,
+			wantValid: true,
+		},
+		{
+			name:      "stringer",
+			v:         reflect.ValueOf(testColor("red")),
+			want:      "color-red",
+			wantValid: true,
+		},
+		{
+			name:      "text marshaler not addressable",
+			v:         reflect.ValueOf(testID("a")),
+			want:      "id-a",
+			wantValid: true,
+		},
+		{
+			name:      "text marshaler addressable",
+			v:         reflect.ValueOf(&[]testID{"b"}[0]).Elem(),
+			want:      "id-b",
+			wantValid: true,
+		},
+		{
+			name:      "numeric stringer unchanged",
+			v:         reflect.ValueOf(testStatus(1)),
+			want:      testStatus(1),
+			wantValid: true,
+		},
+		{
+			name:      "struct stringer integer value",
+			v:         reflect.ValueOf(testDecimal{units: 5}),
+			want:      int64(5),
+			wantValid: true,
+		},
+		{
+			name:      "struct stringer decimal value",
+			v:         reflect.ValueOf(testDecimal{units: 10, cents: 50}),
+			want:      10.5,
+			wantValid: true,
+		},
+		{
+			name:      "struct stringer string value",
+			v:         reflect.ValueOf(testToken{v: "a"}),
+			want:      "token-a",
+			wantValid: true,
+		},
+		{
+			name:      "struct stringer with exported field unchanged",
+			v:         reflect.ValueOf(testAddress{City: "Paris"}),
+			want:      testAddress{City: "Paris"},
+			wantValid: true,
+		},
+		{
+			name:      "defined canonical value",
+			v:         reflect.ValueOf(testOptional{v: &one}),
+			want:      1,
+			wantValid: true,
+		},
+		{
+			name: "nil canonical value",
+			v:    reflect.ValueOf(testOptional{}),
+		},
+		{
+			name:      "time unchanged",
+			v:         reflect.ValueOf(testNow),
+			want:      testNow,
+			wantValid: true,
+		},
+		{
+			name:      "duration unchanged",
+			v:         reflect.ValueOf(time.Minute),
+			want:      time.Minute,
+			wantValid: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := canonicalValue(tt.v)
+			if got.IsValid() != tt.wantValid {
+				t.Fatalf("canonicalValue() valid = %v, want %v", got.IsValid(), tt.wantValid)
+			}
+			if tt.wantValid && !reflect.DeepEqual(got.Interface(), tt.want) {
+				t.Errorf("canonicalValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter_valuer(t *testing.T) {
+	one := 1
+	entries := []testValuerStruct{
+		{
+			Amount:    testAmount{cents: 150},
+			Amounts:   []testAmount{{cents: 100}, {cents: 200}},
+			PtrAmount: &testAmount{cents: 1000},
+			Status:    0,
+			Color:     "red",
+			ID:        "a",
+			Address:   testAddress{City: "Paris"},
+			Price:     testDecimal{units: 4, cents: 99},
+			Optional:  testOptional{v: &one},
+			Any:       testColor("blue"),
+		},
+		{
+			Amount:  testAmount{cents: 1050},
+			Status:  1,
+			Color:   "blue",
+			ID:      "b",
+			Address: testAddress{City: "Lyon"},
+			Price:   testDecimal{units: 10, cents: 50},
+			Any:     testStatus(1),
+		},
+	}
+	tests := []struct {
+		name    string
+		filter  string
+		want    []testValuerStruct
+		wantErr bool
+	}{
+		{
+			name:   "filter valuer equal",
+			filter: "amount=1.5",
+			want:   entries[:1],
+		},
+		{
+			name:   "filter valuer greater than",
+			filter: "amount>10",
+			want:   entries[1:],
+		},
+		{
+			name:   "filter valuer interval",
+			filter: "amount=[1..2]",
+			want:   entries[:1],
+		},
+		{
+			name:   "filter valuer in array",
+			filter: "amounts=2",
+			want:   entries[:1],
+		},
+		{
+			name:   "filter valuer pointer",
+			filter: "ptrAmount>5",
+			want:   entries[:1],
+		},
+		{
+			name:   "nil canonical value",
+			filter: "optional!=2",
+			want:   entries,
+		},
+		{
+			name:   "stringer",
+			filter: "color=color-blue",
+			want:   entries[1:],
+		},
+		{
+			name:   "stringer with string operator",
+			filter: "color$=red",
+			want:   entries[:1],
+		},
+		{
+			name:   "text marshaler",
+			filter: "id=id-b",
+			want:   entries[1:],
+		},
+		{
+			name:   "stringer in interface",
+			filter: "any=color-blue",
+			want:   entries[:1],
+		},
+		{
+			name:   "canonical value with scalar function",
+			filter: "upper(color)=COLOR-RED",
+			want:   entries[:1],
+		},
+		{
+			name:   "numeric stringer equal",
+			filter: "status=1",
+			want:   entries[1:],
+		},
+		{
+			name:   "numeric stringer compared numerically",
+			filter: "status>0",
+			want:   entries[1:],
+		},
+		{
+			name:   "numeric stringer in expression",
+			filter: "status+1=2",
+			want:   entries[1:],
+		},
+		{
+			name:   "numeric stringer equal to its name",
+			filter: "status=open",
+			want:   entries[:1],
+		},
+		{
+			name:   "numeric stringer not equal to its name",
+			filter: "status!=open,2",
+			want:   entries[1:],
+		},
+		{
+			name:   "numeric stringer name in interface",
+			filter: "any=closed",
+			want:   entries[1:],
+		},
+		{
+			name:    "numeric stringer not ordered by its name",
+			filter:  "status>open",
+			wantErr: true,
+		},
+		{
+			name:   "struct stringer greater than",
+			filter: "price>5",
+			want:   entries[1:],
+		},
+		{
+			name:   "struct stringer equal",
+			filter: "price=10.5",
+			want:   entries[1:],
+		},
+		{
+			name:   "struct stringer interval",
+			filter: "price=[4..5)",
+			want:   entries[:1],
+		},
+		{
+			name:   "struct stringer browsed",
+			filter: "addr.city=Paris",
+			want:   entries[:1],
+		},
+		{
+			name:    "filter valuer not browsed",
+			filter:  "amount.cents=150",
+			wantErr: true,
+		},
+		{
+			name:    "filter valuer not numeric in expression",
+			filter:  "amount*2>3",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{}
+			if err := f.Init(tt.filter, testValuerStruct{}); (err != nil) != tt.wantErr {
+				t.Fatalf("Init() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got, err := f.ApplyFilter(entries)
+			if err != nil {
+				t.Fatalf("ApplyFilter() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ApplyFilter() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package jsonFilter
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+/*
+Function of a virtual field. The struct value is the value of the struct which has the virtual field, like an entry or
+an element of an array of struct.
+
+Return the value of the virtual field, of the result type registered with the virtual field. A nil value is ignored,
+like a nil pointer.
+*/
+type VirtualFieldFunc func(structValue reflect.Value) interface{}
+
+// Virtual field registered on a struct type
+type virtualField struct {
+	// Type of the values returned by the function
+	resultType reflect.Type
+	fn         VirtualFieldFunc
+}
+
+/*
+Register a virtual field on a struct type, like a value computed from several struct fields. The name of the virtual
+field can be used in the filter keys like a struct field, like fullName=alice or items.isOverdue=true. The struct
+fields are found before the virtual fields.
+
+Register the virtual fields before the initialization, with a value of the struct type:
+
+	filter := jsonFilter.Filter{}
+	err := filter.RegisterVirtualField(person{}, "fullName", reflect.TypeOf(""), func(v reflect.Value) interface{} {
+		p := v.Interface().(person)
+		return p.First + " " + p.Last
+	})
+
+An error is returned if the type isn't a struct, if the name is empty, already a struct field name or already a virtual
+field of the type, or if the result type or the function is nil.
+*/
+func (f *Filter) RegisterVirtualField(i interface{}, name string, resultType reflect.Type, fn VirtualFieldFunc) error {
+	t := reflect.TypeOf(i)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch {
+	case t == nil || t.Kind() != reflect.Struct:
+		return errors.New(fmt.Sprintf("The virtual field %s can't be registered on the type %v which isn't a struct", name, t))
+	case name == "":
+		return errors.New(fmt.Sprintf("The virtual field name of the type %s can't be empty", t))
+	case resultType == nil:
+		return errors.New(fmt.Sprintf("The result type of the virtual field %s can't be nil", name))
+	case fn == nil:
+		return errors.New(fmt.Sprintf("The function of the virtual field %s can't be nil", name))
+	}
+	if _, ok := t.FieldByName(name); ok {
+		return errors.New(fmt.Sprintf("The virtual field %s is already a field of the type %s", name, t))
+	}
+	if _, ok := f.virtualFields[t][name]; ok {
+		return errors.New(fmt.Sprintf("The virtual field %s is already registered on the type %s", name, t))
+	}
+
+	if f.virtualFields == nil {
+		f.virtualFields = make(map[reflect.Type]map[string]virtualField)
+	}
+	if f.virtualFields[t] == nil {
+		f.virtualFields[t] = make(map[string]virtualField)
+	}
+	f.virtualFields[t][name] = virtualField{resultType: resultType, fn: fn}
+	return nil
+}
+
+// Get the virtual field of the struct type.
+// Return false if the virtual field isn't registered on the type
+func (f *Filter) findVirtualField(name string, t reflect.Type) (virtualField, bool) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	vf, ok := f.virtualFields[t][name]
+	return vf, ok
+}
+
+// Compute the value of the virtual field on the struct value.
+// Return false if the value is nil or isn't of the result type
+func (vf virtualField) value(v reflect.Value) (reflect.Value, bool) {
+	res := reflect.ValueOf(vf.fn(v))
+	if !res.IsValid() || res.Type() != vf.resultType {
+		return res, false
+	}
+	return res, true
+}
@@ -0,0 +1,215 @@
+package jsonFilter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Full string of the entry, computed from the root string and the sub string of the root struct
+func fullString(v reflect.Value) interface{} {
+	e := v.Interface().(testStruct)
+	return e.RootString + " " + e.RootStruct.SubString
+}
+
+// Upper case of the sub string, nil if the sub string is empty
+func upperSubString(v reflect.Value) interface{} {
+	s := v.Interface().(SubStruct)
+	if s.SubString == "" {
+		return nil
+	}
+	return strings.ToUpper(s.SubString)
+}
+
+func TestFilter_RegisterVirtualField(t *testing.T) {
+	tests := []struct {
+		name       string
+		i          interface{}
+		vfName     string
+		resultType reflect.Type
+		fn         VirtualFieldFunc
+		wantErr    bool
+	}{
+		{
+			name:       "virtual field",
+			i:          testStruct{},
+			vfName:     "fullString",
+			resultType: stringType,
+			fn:         fullString,
+		},
+		{
+			name:       "virtual field on pointer",
+			i:          &testStruct{},
+			vfName:     "fullString",
+			resultType: stringType,
+			fn:         fullString,
+		},
+		{
+			name:       "not a struct",
+			i:          "",
+			vfName:     "fullString",
+			resultType: stringType,
+			fn:         fullString,
+			wantErr:    true,
+		},
+		{
+			name:       "nil type",
+			vfName:     "fullString",
+			resultType: stringType,
+			fn:         fullString,
+			wantErr:    true,
+		},
+		{
+			name:       "empty name",
+			i:          testStruct{},
+			resultType: stringType,
+			fn:         fullString,
+			wantErr:    true,
+		},
+		{
+			name:    "nil result type",
+			i:       testStruct{},
+			vfName:  "fullString",
+			fn:      fullString,
+			wantErr: true,
+		},
+		{
+			name:       "nil function",
+			i:          testStruct{},
+			vfName:     "fullString",
+			resultType: stringType,
+			wantErr:    true,
+		},
+		{
+			name:       "struct field name",
+			i:          testStruct{},
+			vfName:     "RootString",
+			resultType: stringType,
+			fn:         fullString,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{}
+			if err := f.RegisterVirtualField(tt.i, tt.vfName, tt.resultType, tt.fn); (err != nil) != tt.wantErr {
+				t.Errorf("RegisterVirtualField() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFilter_RegisterVirtualField_twice(t *testing.T) {
+	f := &Filter{}
+	if err := f.RegisterVirtualField(testStruct{}, "fullString", stringType, fullString); err != nil {
+		t.Fatalf("RegisterVirtualField() error = %v", err)
+	}
+	if err := f.RegisterVirtualField(testStruct{}, "fullString", stringType, fullString); err == nil {
+		t.Errorf("RegisterVirtualField() error = nil, want already registered error")
+	}
+	// The same name can be registered on another type
+	if err := f.RegisterVirtualField(SubStruct{}, "fullString", stringType, upperSubString); err != nil {
+		t.Errorf("RegisterVirtualField() error = %v", err)
+	}
+}
+
+func TestFilter_virtualField(t *testing.T) {
+	entries := []testStruct{
+		{RootString: "value1", RootStruct: SubStruct{SubString: "sub1"}, RootArray: []SubStruct{{SubString: "a"}, {}}},
+		{RootString: "value2", RootStruct: SubStruct{SubString: "sub2"}, RootArray: []SubStruct{{SubString: "b"}}},
+		{RootString: "value3", RootArray: []SubStruct{{}}},
+	}
+	tests := []struct {
+		name    string
+		filter  string
+		wantKey string
+		want    []testStruct
+		wantErr bool
+	}{
+		{
+			name:    "virtual field on the entry",
+			filter:  "fullString=value1 sub1,value3 ",
+			wantKey: "fullString",
+			want:    []testStruct{entries[0], entries[2]},
+		},
+		{
+			name:    "virtual field with string operator",
+			filter:  "fullString$=sub2",
+			wantKey: "fullString",
+			want:    entries[1:2],
+		},
+		{
+			name:    "virtual field in array of struct",
+			filter:  "arrayRoot.upperSub=A,B",
+			wantKey: "RootArray.upperSub",
+			want:    entries[:2],
+		},
+		{
+			name:    "nil virtual field value ignored",
+			filter:  "missing(arrayRoot.upperSub)",
+			wantKey: "RootArray.upperSub",
+			want:    entries[2:],
+		},
+		{
+			name:    "virtual field in function",
+			filter:  "len(fullString)>7",
+			wantKey: "len(fullString)",
+			want:    entries[:2],
+		},
+		{
+			name:    "virtual field in element match",
+			filter:  "arrayRoot{upperSub=B}",
+			wantKey: "RootArray",
+			want:    entries[1:2],
+		},
+		{
+			name:    "virtual field reference",
+			filter:  "fullString!=$stringRoot",
+			wantKey: "fullString",
+			want:    entries,
+		},
+		{
+			name:    "virtual field not registered on the type",
+			filter:  "structRoot.fullString=value1",
+			wantErr: true,
+		},
+		{
+			name:    "virtual field browsed deeper than its type",
+			filter:  "fullString.sub=value1",
+			wantErr: true,
+		},
+		{
+			name:    "virtual field value not compliant with its type",
+			filter:  "fullString>1,2",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{}
+			if err := f.RegisterVirtualField(testStruct{}, "fullString", stringType, fullString); err != nil {
+				t.Fatalf("RegisterVirtualField() error = %v", err)
+			}
+			if err := f.RegisterVirtualField(SubStruct{}, "upperSub", stringType, upperSubString); err != nil {
+				t.Fatalf("RegisterVirtualField() error = %v", err)
+			}
+			err := f.Init(tt.filter, testStruct{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Init() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if f.filter[0].Key != tt.wantKey {
+				t.Errorf("Init() key = %v, want %v", f.filter[0].Key, tt.wantKey)
+			}
+			got, err := f.ApplyFilter(entries)
+			if err != nil {
+				t.Fatalf("ApplyFilter() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ApplyFilter() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}